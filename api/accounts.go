@@ -8,17 +8,33 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/vinymeuh/gnc-api-d/models"
 )
 
 type AccountsHandler struct {
-	Data *models.Account
+	Data *models.Book
 }
 
 func (ah *AccountsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	path := strings.Split(r.URL.Path, "/")
+	if len(path) > 3 && path[2] == "by-path" { // /accounts/by-path/{:path}
+		// split the escaped path so that a "/" in the name of an account can be sent as %2F
+		elements := strings.Split(r.URL.EscapedPath(), "/")[3:]
+		for i, e := range elements {
+			unescaped, err := url.PathUnescape(e)
+			if err != nil {
+				httpBadRequest(w, r)
+				return
+			}
+			elements[i] = unescaped
+		}
+		ah.serveAccountByPath(w, r, elements)
+		return
+	}
 	switch len(path) { // +1 for leading /
 	case 2:
 		ah.serveAccountsByNameOrType(w, r)
@@ -37,16 +53,42 @@ func (ah *AccountsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (ah *AccountsHandler) serveAccountsByNameOrType(w http.ResponseWriter, r *http.Request) {
 	var acts []*models.Account
 
+	// accounts are selected by name, type or path, then filtered by their flags
 	params := r.URL.Query()
-	switch len(params) {
+	flags := make(map[string]bool)
+	selectors := 0
+	for key := range params {
+		switch key {
+		case "name", "type", "path":
+			selectors++
+		case "hidden", "placeholder", "tax_related":
+			v, err := strconv.ParseBool(params.Get(key))
+			if err != nil {
+				httpBadRequest(w, r)
+				return
+			}
+			flags[key] = v
+		default:
+			httpBadRequest(w, r)
+			return
+		}
+	}
+
+	switch selectors {
 	case 0:
-		acts = ah.Data.Descendants()
+		acts = ah.Data.Accounts()
 	case 1:
 		if name := params.Get("name"); name != "" {
-			acts = ah.Data.FindByName(name)
+			acts = ah.Data.AccountsByName(name)
 		}
 		if atype := params.Get("type"); atype != "" {
-			acts = ah.Data.FindByType(atype)
+			acts = ah.Data.AccountsByType(atype)
+		}
+		if path := params.Get("path"); path != "" {
+			acts = make([]*models.Account, 0, 1)
+			if act := ah.Data.AccountByPath(path); act != nil {
+				acts = append(acts, act)
+			}
 		}
 	}
 
@@ -55,6 +97,23 @@ func (ah *AccountsHandler) serveAccountsByNameOrType(w http.ResponseWriter, r *h
 		return
 	}
 
+	if len(flags) > 0 {
+		found := make([]*models.Account, 0, len(acts))
+		for _, act := range acts {
+			if v, ok := flags["hidden"]; ok && act.Hidden != v {
+				continue
+			}
+			if v, ok := flags["placeholder"]; ok && act.Placeholder != v {
+				continue
+			}
+			if v, ok := flags["tax_related"]; ok && act.TaxRelated != v {
+				continue
+			}
+			found = append(found, act)
+		}
+		acts = found
+	}
+
 	resp, err := json.Marshal(acts)
 	if err != nil {
 		log.Printf("Unable to marshall all accounts to JSON: %s\n", err)
@@ -66,7 +125,23 @@ func (ah *AccountsHandler) serveAccountsByNameOrType(w http.ResponseWriter, r *h
 }
 
 func (ah *AccountsHandler) serveAccountByID(w http.ResponseWriter, r *http.Request, id string) {
-	act := ah.Data.FindByID(id)
+	ah.serveAccount(w, r, ah.Data.Account(id))
+}
+
+// serveAccountByPath accepts the full path of the account as a single element
+// using the path separator ("Expenses:Auto:Fuel") or split in several elements ("Expenses/Auto/Fuel").
+// Elements are unescaped, so "Expenses/Entertainment/Music%2FMovies" finds the account "Music/Movies".
+func (ah *AccountsHandler) serveAccountByPath(w http.ResponseWriter, r *http.Request, elements []string) {
+	for _, e := range elements {
+		if e == "" {
+			httpBadRequest(w, r)
+			return
+		}
+	}
+	ah.serveAccount(w, r, ah.Data.AccountByPath(strings.Join(elements, models.PathSeparator)))
+}
+
+func (ah *AccountsHandler) serveAccount(w http.ResponseWriter, r *http.Request, act *models.Account) {
 	if act != nil {
 		resp, err := json.Marshal(act)
 		if err != nil {
@@ -28,7 +28,7 @@ func TestAccountsHandlerByID(t *testing.T) {
 		ID:   "0",
 		Type: "ROOT",
 	}
-	h := AccountsHandler{Data: &acts}
+	h := AccountsHandler{Data: &models.Book{Root: &acts}}
 
 	for _, tt := range accountsByIDTests {
 		req, err := http.NewRequest(tt.method, tt.path, nil)
@@ -63,7 +63,7 @@ func TestAccountsHandlerByNameOrType(t *testing.T) {
 		Type: "ROOT",
 		Name: "Dummy",
 	}
-	h := AccountsHandler{Data: &acts}
+	h := AccountsHandler{Data: &models.Book{Root: &acts}}
 
 	for _, tt := range accountsByNameOrTypeTests {
 		req, err := http.NewRequest(tt.method, tt.path, nil)
@@ -82,3 +82,116 @@ func TestAccountsHandlerByNameOrType(t *testing.T) {
 		assert.Equal(t, tt.count, len(body), "number of results does not match")
 	}
 }
+
+var accountsByPathTests = []struct {
+	path   string
+	status int
+	id     string
+}{
+	{"/accounts/by-path/Expenses:Auto:Fuel", http.StatusOK, "3"},
+	{"/accounts/by-path/Expenses/Home/Fuel", http.StatusOK, "5"},
+	{"/accounts/by-path/Expenses", http.StatusOK, "1"},
+	{"/accounts/by-path/Expenses:Fuel", http.StatusNotFound, ""},
+	{"/accounts/by-path/", http.StatusBadRequest, ""},
+	{"/accounts/by-path/Expenses//Fuel", http.StatusBadRequest, ""},
+	{"/accounts/by-path/Expenses:Music%2FMovies", http.StatusOK, "6"},
+	{"/accounts/by-path/Expenses/Music%2FMovies", http.StatusOK, "6"},
+	{"/accounts/by-path/Expenses/Music/Movies", http.StatusNotFound, ""},
+	{"/accounts?path=Expenses:Home", http.StatusOK, "4"},
+	{"/accounts?path=Expenses:Fuel", http.StatusOK, ""},
+}
+
+func TestAccountsHandlerByPath(t *testing.T) {
+	root := &models.Account{ID: "0", Name: "Root Account", Type: "ROOT"}
+	expenses := &models.Account{ID: "1", Name: "Expenses", Type: "EXPENSE", Parent: root}
+	auto := &models.Account{ID: "2", Name: "Auto", Type: "EXPENSE", Parent: expenses}
+	home := &models.Account{ID: "4", Name: "Home", Type: "EXPENSE", Parent: expenses}
+	music := &models.Account{ID: "6", Name: "Music/Movies", Type: "EXPENSE", Parent: expenses}
+	root.Children = []*models.Account{expenses}
+	expenses.Children = []*models.Account{auto, home, music}
+	auto.Children = []*models.Account{{ID: "3", Name: "Fuel", Type: "EXPENSE", Parent: auto}}
+	home.Children = []*models.Account{{ID: "5", Name: "Fuel", Type: "EXPENSE", Parent: home}}
+	h := AccountsHandler{Data: &models.Book{Root: root}}
+
+	for _, tt := range accountsByPathTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var acts []*models.Account
+		if req.URL.RawQuery == "" {
+			act := new(models.Account)
+			json.NewDecoder(res.Body).Decode(act)
+			acts = append(acts, act)
+		} else {
+			json.NewDecoder(res.Body).Decode(&acts)
+		}
+		if tt.id == "" {
+			assert.Equal(t, 0, len(acts), "no account should be found for %s", tt.path)
+		} else if assert.Equal(t, 1, len(acts), "one account should be found for %s", tt.path) {
+			assert.Equal(t, tt.id, acts[0].ID, "account found does not match for %s", tt.path)
+			assert.Contains(t, req.URL.Path+req.URL.RawQuery, acts[0].Name, "account found does not match for %s", tt.path)
+			assert.NotEmpty(t, acts[0].Path, "path of account is missing for %s", tt.path)
+		}
+	}
+}
+
+var accountsFilterTests = []struct {
+	path   string
+	status int
+	ids    []string
+}{
+	{"/accounts?hidden=false", http.StatusOK, []string{"1", "2", "3"}},
+	{"/accounts?hidden=true", http.StatusOK, []string{"4", "5"}},
+	{"/accounts?placeholder=false&hidden=false", http.StatusOK, []string{"2", "3"}},
+	{"/accounts?type=BANK&hidden=0", http.StatusOK, []string{"2"}},
+	{"/accounts?tax_related=true", http.StatusOK, []string{"5"}},
+	{"/accounts?hidden=maybe", http.StatusBadRequest, nil},
+	{"/accounts?type=BANK&name=Bank", http.StatusBadRequest, nil},
+}
+
+func TestAccountsHandlerFilters(t *testing.T) {
+	root := &models.Account{ID: "0", Name: "Root Account", Type: "ROOT"}
+	root.Children = []*models.Account{
+		{ID: "1", Name: "Assets", Type: "ASSET", Placeholder: true, Parent: root},
+		{ID: "2", Name: "Bank", Type: "BANK", Parent: root},
+		{ID: "3", Name: "Cash", Type: "CASH", Parent: root},
+		{ID: "4", Name: "Old Bank", Type: "BANK", Hidden: true, Parent: root},
+		{ID: "5", Name: "Dividends", Type: "INCOME", Hidden: true, TaxRelated: true, Parent: root},
+	}
+	h := AccountsHandler{Data: &models.Book{Root: root}}
+
+	for _, tt := range accountsFilterTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var acts []struct {
+			ID string `json:"id"`
+		}
+		json.NewDecoder(res.Body).Decode(&acts)
+		ids := make([]string, 0)
+		for _, a := range acts {
+			ids = append(ids, a.ID)
+		}
+		assert.Equal(t, tt.ids, ids, "accounts do not match for %s", tt.path)
+	}
+}
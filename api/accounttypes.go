@@ -13,17 +13,11 @@ import (
 )
 
 type AccountTypesHandler struct {
-	Data *models.Account
+	Data *models.Book
 }
 
 func (ath *AccountTypesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	types := make(map[string]int)
-	ath.Data.WalkBFS(func(act *models.Account) bool {
-		types[act.Type]++
-		return true
-	})
-
-	resp, err := json.Marshal(types)
+	resp, err := json.Marshal(ath.Data.AccountTypes())
 	if err != nil {
 		log.Printf("Unable to marshall all accounts to JSON: %s\n", err)
 		httpInternalServerError(w, r)
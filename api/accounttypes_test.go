@@ -29,7 +29,7 @@ func TestAccountTypesHandler(t *testing.T) {
 			},
 		},
 	}
-	h := AccountTypesHandler{Data: &acts}
+	h := AccountTypesHandler{Data: &models.Book{Root: &acts}}
 
 	w := httptest.NewRecorder()
 	h.ServeHTTP(w, nil)
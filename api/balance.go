@@ -5,26 +5,55 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/vinymeuh/gnc-api-d/models"
 )
 
 type BalanceHandler struct {
-	Data *models.Account // Root !
+	Data *models.Book
 }
 
 func (bh *BalanceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	id := strings.Split(r.URL.Path, "/")[2]
 	log.Printf("id = %s", id)
-	act := bh.Data.FindByID(id)
+	act := bh.Data.Account(id)
+	if act == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	opts, err := balanceOptions(r.URL.Query(), bh.Data)
+	if err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	log.Printf("%v", opts)
+
+	value := act.Balance(opts)
+
+	resp, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Unable to marshall balance: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+	log.Printf("%s", resp)
+	return
+}
 
+// balanceOptions reads the parameters shared by all handlers computing balances
+func balanceOptions(params url.Values, book *models.Book) (models.BalanceOptions, error) {
 	opts := models.BalanceOptions{Recursive: true}
-	params := r.URL.Query()
 	if _, ok := params["norecursive"]; ok {
 		opts.Recursive = false
 	}
@@ -37,18 +66,47 @@ func (bh *BalanceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if aType := params.Get("type"); aType != "" {
 		opts.Type = aType
 	}
-	log.Printf("%v", opts)
+	switch params.Get("amount") {
+	case "", "value":
+	case "quantity":
+		opts.Quantity = true
+	default:
+		return opts, errors.New("amount must be 'value' or 'quantity'")
+	}
+	return opts, currencyOption(params, book, &opts)
+}
 
-	value := act.Balance(opts)
+// unsupportedOptions reports whether one of the parameters names is given, for the handlers
+// not using all the parameters of balanceOptions
+func unsupportedOptions(params url.Values, names ...string) bool {
+	for _, name := range names {
+		if _, ok := params[name]; ok {
+			return true
+		}
+	}
+	return false
+}
 
-	resp, err := json.Marshal(value)
-	if err != nil {
-		log.Printf("Unable to marshall balance: %s\n", err)
-		httpInternalServerError(w, r)
-		return
+// currencyOption sets the currency used to convert amounts and the prices of the book
+func currencyOption(params url.Values, book *models.Book, opts *models.BalanceOptions) error {
+	if currency := params.Get("currency"); currency != "" {
+		opts.Currency = findCommodity(book.Commodities, currency)
+		if opts.Currency == nil {
+			return errors.New("unknown currency")
+		}
+		opts.Prices = book.Prices
 	}
-	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, "%s", resp)
-	log.Printf("%s", resp)
-	return
+	return nil
+}
+
+// dateOption reads a date parameter written as YYYY-MM-DD
+func dateOption(params url.Values, name string) (string, error) {
+	date := params.Get(name)
+	if date == "" {
+		return "", nil
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return "", err
+	}
+	return date, nil
 }
@@ -17,14 +17,15 @@ var balanceTests = []struct {
 	path    string
 	status  int
 	balance float64
+	amount  string
 	errmsg  string
 }{
-	{"/balance/1", http.StatusOK, -100.0, "current balance for account 1 is wrong"},
-	{"/balance/2", http.StatusOK, -15.5, "current balance for account 2 is wrong"},
-	{"/balance/0", http.StatusOK, 1374.5, "recursive current balance for account 0 is wrong"},
-	{"/balance/0?norecursive", http.StatusOK, 1490.0, "non recursive current balance for account 0 is wrong"},
-	{"/balance/0?from=2019-02-01&to=2019-02-20", http.StatusOK, 484.5, "recursive account balance between two dates is wrong"},
-	{"/balance/0?type=X", http.StatusOK, -10.0, "account balance for a given type is wrong"},
+	{"/balance/1", http.StatusOK, -100.0, "-100.00", "current balance for account 1 is wrong"},
+	{"/balance/2", http.StatusOK, -15.5, "-15.50", "current balance for account 2 is wrong"},
+	{"/balance/0", http.StatusOK, 1374.5, "1374.50", "recursive current balance for account 0 is wrong"},
+	{"/balance/0?norecursive", http.StatusOK, 1490.0, "1490.00", "non recursive current balance for account 0 is wrong"},
+	{"/balance/0?from=2019-02-01&to=2019-02-20", http.StatusOK, 484.5, "484.50", "recursive account balance between two dates is wrong"},
+	{"/balance/0?type=X", http.StatusOK, -10.0, "-10.00", "account balance for a given type is wrong"},
 }
 
 func TestBalance(t *testing.T) {
@@ -32,33 +33,33 @@ func TestBalance(t *testing.T) {
 		ID:   "0",
 		Name: "Dummy Account",
 		Type: "ROOT",
-		Transactions: []*models.Transaction{
-			{Date: "2019-01-01", Value: 1000.0},
-			{Date: "2019-01-02", Value: -9.5, Num: "X"},
-			{Date: "2019-01-03", Value: -0.5, Num: "X"},
-			{Date: "2019-02-01", Value: 1000.0},
-			{Date: "2019-02-03", Value: -500.0},
+		Splits: []*models.Split{
+			{Value: models.NewAmount(100000, 100), Transaction: &models.Transaction{DatePosted: "2019-01-01"}},
+			{Value: models.NewAmount(-950, 100), Transaction: &models.Transaction{DatePosted: "2019-01-02", Num: "X"}},
+			{Value: models.NewAmount(-50, 100), Transaction: &models.Transaction{DatePosted: "2019-01-03", Num: "X"}},
+			{Value: models.NewAmount(100000, 100), Transaction: &models.Transaction{DatePosted: "2019-02-01"}},
+			{Value: models.NewAmount(-50000, 100), Transaction: &models.Transaction{DatePosted: "2019-02-03"}},
 		},
 		Children: []*models.Account{
 			{
 				ID:   "1",
 				Name: "Account 1",
 				Type: "BANK",
-				Transactions: []*models.Transaction{
-					{Date: "2019-01-05", Value: -100.0},
+				Splits: []*models.Split{
+					{Value: models.NewAmount(-10000, 100), Transaction: &models.Transaction{DatePosted: "2019-01-05"}},
 				},
 			},
 			{
 				ID:   "2",
 				Name: "Account 2",
 				Type: "BANK",
-				Transactions: []*models.Transaction{
-					{Date: "2019-02-20", Value: -15.5},
+				Splits: []*models.Split{
+					{Value: models.NewAmount(-1550, 100), Transaction: &models.Transaction{DatePosted: "2019-02-20"}},
 				},
 			},
 		},
 	}
-	h := BalanceHandler{Data: &acts}
+	h := BalanceHandler{Data: &models.Book{Root: &acts}}
 
 	for _, tt := range balanceTests {
 		req, err := http.NewRequest("GET", tt.path, nil)
@@ -74,5 +75,121 @@ func TestBalance(t *testing.T) {
 		var balance models.Balance
 		json.NewDecoder(res.Body).Decode(&balance)
 		assert.Equal(t, tt.balance, balance.Value, tt.errmsg)
+		assert.Equal(t, tt.amount, balance.Amount.String(), tt.errmsg)
+	}
+}
+
+var balanceCurrencyTests = []struct {
+	path     string
+	status   int
+	amount   string
+	unpriced int
+	mixed    int
+}{
+	{"/balance/0?to=2019-01-31", http.StatusOK, "225.00", 0, 2},
+	{"/balance/0?to=2019-01-05", http.StatusOK, "100.00", 0, 0},
+	{"/balance/0?to=2019-01-31&currency=EUR", http.StatusOK, "200.00", 0, 0},
+	{"/balance/0?to=2019-01-31&currency=CURRENCY:USD", http.StatusOK, "250.00", 0, 0},
+	{"/balance/0?to=2018-12-31&currency=USD", http.StatusOK, "0.00", 0, 0},
+	{"/balance/0?to=2019-01-05&currency=USD", http.StatusOK, "0.00", 1, 0},
+	{"/balance/0?currency=XXX", http.StatusBadRequest, "", 0, 0},
+	{"/balance/666", http.StatusNotFound, "", 0, 0},
+}
+
+func TestBalanceWithCurrency(t *testing.T) {
+	eur := &models.Commodity{Space: models.CurrencySpace, ID: "EUR", Fraction: 100}
+	usd := &models.Commodity{Space: models.CurrencySpace, ID: "USD", Fraction: 100}
+	acts := models.Account{
+		ID:        "0",
+		Type:      "ASSET",
+		Commodity: eur,
+		Splits: []*models.Split{
+			{Value: models.NewAmount(10000, 100), Quantity: models.NewAmount(10000, 100), Transaction: &models.Transaction{DatePosted: "2019-01-01"}},
+		},
+		Children: []*models.Account{
+			{
+				ID:        "1",
+				Type:      "BANK",
+				Commodity: usd,
+				Splits: []*models.Split{
+					{Value: models.NewAmount(12500, 100), Quantity: models.NewAmount(12500, 100), Transaction: &models.Transaction{DatePosted: "2019-01-15"}},
+				},
+			},
+		},
+	}
+	h := BalanceHandler{Data: &models.Book{
+		Root:        &acts,
+		Commodities: models.Commodities{eur, usd},
+		Prices: models.NewPriceDB([]*models.Price{
+			{Commodity: eur, Currency: usd, Date: "2019-01-10", Value: models.NewAmount(125, 100)},
+		}),
+	}}
+
+	for _, tt := range balanceCurrencyTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var balance models.Balance
+		json.NewDecoder(res.Body).Decode(&balance)
+		assert.Equal(t, tt.amount, balance.Amount.String(), "balance is wrong for %s", tt.path)
+		assert.Equal(t, tt.unpriced, len(balance.Unpriced), "unpriced commodities are wrong for %s", tt.path)
+		assert.Equal(t, tt.mixed, len(balance.Mixed), "mixed commodities are wrong for %s", tt.path)
+	}
+}
+
+var balanceQuantityTests = []struct {
+	path   string
+	status int
+	amount string
+}{
+	{"/balance/1", http.StatusOK, "750.00"},
+	{"/balance/1?amount=value", http.StatusOK, "750.00"},
+	{"/balance/1?amount=quantity", http.StatusOK, "5"},
+	{"/balance/1?amount=shares", http.StatusBadRequest, ""},
+}
+
+func TestBalanceWithQuantity(t *testing.T) {
+	acts := models.Account{
+		ID:   "0",
+		Type: "ASSET",
+		Children: []*models.Account{
+			{
+				ID:   "1",
+				Type: "STOCK",
+				Splits: []*models.Split{
+					{Value: models.NewAmount(75000, 100), Quantity: models.NewAmount(5, 1), Transaction: &models.Transaction{DatePosted: "2019-02-01"}},
+				},
+			},
+		},
+	}
+	h := BalanceHandler{Data: &models.Book{Root: &acts}}
+
+	for _, tt := range balanceQuantityTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var balance models.Balance
+		json.NewDecoder(res.Body).Decode(&balance)
+		assert.Equal(t, tt.amount, balance.Amount.String(), "balance is wrong for %s", tt.path)
 	}
 }
@@ -0,0 +1,45 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type BalanceSheetHandler struct {
+	Data *models.Book
+}
+
+func (bh *BalanceSheetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	if unsupportedOptions(params, "type", "norecursive", "amount") {
+		httpBadRequest(w, r)
+		return
+	}
+	var opts models.BalanceOptions
+	if err := currencyOption(params, bh.Data, &opts); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	date, err := dateOption(params, "date")
+	if err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	opts.To = date
+
+	resp, err := json.Marshal(bh.Data.BalanceSheet(opts))
+	if err != nil {
+		log.Printf("Unable to marshall balance sheet to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var balanceSheetTests = []struct {
+	path   string
+	status int
+	assets string
+}{
+	{"/reports/balance-sheet", http.StatusOK, "-235.50"},
+	{"/reports/balance-sheet?date=2019-01-31", http.StatusOK, "-105.50"},
+	{"/reports/balance-sheet?date=2019-12", http.StatusBadRequest, ""},
+	{"/reports/balance-sheet?currency=XXX", http.StatusBadRequest, ""},
+	{"/reports/balance-sheet?amount=quantity", http.StatusBadRequest, ""},
+	{"/reports/balance-sheet?norecursive", http.StatusBadRequest, ""},
+}
+
+func TestBalanceSheetHandler(t *testing.T) {
+	trns := testTransactions()
+	root := models.Account{ID: "0", Type: "ROOT"}
+	for _, act := range []*models.Account{trns[0].Splits[0].Account, trns[0].Splits[1].Account, trns[1].Splits[1].Account} {
+		act.Parent = &root
+		root.Children = append(root.Children, act)
+	}
+	h := BalanceSheetHandler{Data: &models.Book{Root: &root}}
+
+	for _, tt := range balanceSheetTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var bs models.BalanceSheet
+		json.NewDecoder(res.Body).Decode(&bs)
+		assert.Equal(t, tt.assets, bs.Assets.Total.String(), "total of assets does not match for %s", tt.path)
+		assert.True(t, bs.Balanced, "balance sheet is not balanced for %s", tt.path)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type BookSlotsHandler struct {
+	Data models.Slots
+}
+
+func (bh *BookSlotsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	if len(path) != 3 || path[2] != "slots" { // /books/slots
+		httpBadRequest(w, r)
+		return
+	}
+
+	var value interface{} = bh.Data
+	if bh.Data == nil {
+		value = make(models.Slots)
+	}
+	if key := r.URL.Query().Get("key"); key != "" {
+		slot, ok := bh.Data.Get(key)
+		if !ok {
+			httpNotFound(w, r)
+			return
+		}
+		value = slot
+	}
+
+	resp, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Unable to marshall book slots to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
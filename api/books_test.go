@@ -0,0 +1,61 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var bookSlotsTests = []struct {
+	path   string
+	status int
+	body   string
+}{
+	{"/books/slots", http.StatusOK, `{"options":{"type":"frame","value":{"Business":{"type":"frame","value":{"Company Name":{"type":"string","value":"VinyMeuh"}}}}}}`},
+	{"/books/slots?key=options/Business/Company%20Name", http.StatusOK, `{"type":"string","value":"VinyMeuh"}`},
+	{"/books/slots?key=options/Budgeting", http.StatusNotFound, ""},
+	{"/books/options", http.StatusBadRequest, ""},
+}
+
+func TestBookSlotsHandler(t *testing.T) {
+	slots := models.Slots{
+		"options": {Type: models.SlotFrame, Value: models.Slots{
+			"Business": {Type: models.SlotFrame, Value: models.Slots{
+				"Company Name": {Type: models.SlotString, Value: "VinyMeuh"},
+			}},
+		}},
+	}
+	h := BookSlotsHandler{Data: slots}
+
+	for _, tt := range bookSlotsTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, tt.body, string(body), "Response body is wrong for %s", tt.path)
+	}
+
+	h = BookSlotsHandler{}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/books/slots", nil))
+	assert.Equal(t, "{}", w.Body.String(), "Response body is wrong for a book without slots")
+}
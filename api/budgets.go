@@ -0,0 +1,88 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type BudgetsHandler struct {
+	Data models.Budgets
+}
+
+func (bh *BudgetsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	var data interface{}
+	switch len(path) { // +1 for leading /
+	case 2:
+		budgets := bh.Data
+		if budgets == nil {
+			budgets = make(models.Budgets, 0)
+		}
+		data = budgets
+	case 3:
+		id := path[2]
+		if id == "" {
+			httpBadRequest(w, r)
+			return
+		}
+		bg := bh.Data.FindByID(id)
+		if bg == nil {
+			httpNotFound(w, r)
+			return
+		}
+		data = bg
+	default:
+		httpBadRequest(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Unable to marshall budgets to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
+
+type BudgetReportHandler struct {
+	Data *models.Book
+}
+
+func (bh *BudgetReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	if len(path) != 4 || path[3] != "report" { // /budgets/{:id}/report
+		httpBadRequest(w, r)
+		return
+	}
+
+	bg := bh.Data.Budgets.FindByID(path[2])
+	if bg == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	var opts models.BalanceOptions
+	if err := currencyOption(r.URL.Query(), bh.Data, &opts); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(bh.Data.BudgetReport(bg, opts))
+	if err != nil {
+		log.Printf("Unable to marshall budget report to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
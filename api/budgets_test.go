@@ -0,0 +1,123 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+func testBudgets() models.Budgets {
+	fifty := models.NewAmount(5000, 100)
+	hundred := models.NewAmount(10000, 100)
+	return models.Budgets{
+		{
+			ID:         "b1",
+			Name:       "2019",
+			NumPeriods: 2,
+			Recurrence: models.Recurrence{Period: models.Monthly, Multiplier: 1, Start: "2019-01-01"},
+			Periods:    []models.BudgetPeriod{{Start: "2019-01-01", End: "2019-01-31"}, {Start: "2019-02-01", End: "2019-02-28"}},
+			Accounts: []models.BudgetAccount{
+				{Account: models.AccountRef{ID: "2", Name: "Fuel"}, Amounts: []*models.Amount{&fifty, &fifty}, Total: hundred},
+				{Account: models.AccountRef{ID: "3", Name: "Food"}, Amounts: []*models.Amount{&hundred, nil}, Total: hundred},
+			},
+		},
+	}
+}
+
+var budgetsTests = []struct {
+	path   string
+	status int
+	count  int
+}{
+	{"/budgets", http.StatusOK, 1},
+	{"/budgets/b1", http.StatusOK, 1},
+	{"/budgets/b666", http.StatusNotFound, 0},
+	{"/budgets/", http.StatusBadRequest, 0},
+}
+
+func TestBudgetsHandler(t *testing.T) {
+	h := BudgetsHandler{Data: testBudgets()}
+
+	for _, tt := range budgetsTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var budgets []models.Budget
+		if tt.path == "/budgets" {
+			json.NewDecoder(res.Body).Decode(&budgets)
+		} else {
+			var bg models.Budget
+			json.NewDecoder(res.Body).Decode(&bg)
+			budgets = append(budgets, bg)
+		}
+		if assert.Equal(t, tt.count, len(budgets), "number of budgets does not match for %s", tt.path) {
+			assert.Equal(t, "2019", budgets[0].Name, "name of budget does not match for %s", tt.path)
+			assert.Nil(t, budgets[0].Accounts[1].Amounts[1], "amount not budgeted does not match for %s", tt.path)
+		}
+	}
+
+	h = BudgetsHandler{}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/budgets", nil))
+	assert.Equal(t, "[]", w.Body.String(), "Response body is wrong for a book without budgets")
+}
+
+var budgetReportTests = []struct {
+	path     string
+	status   int
+	variance string
+}{
+	{"/budgets/b1/report", http.StatusOK, "-75.50"},
+	{"/budgets/b666/report", http.StatusNotFound, ""},
+	{"/budgets/b1/report?currency=XXX", http.StatusBadRequest, ""},
+	{"/budgets/b1/summary", http.StatusBadRequest, ""},
+}
+
+func TestBudgetReportHandler(t *testing.T) {
+	trns := testTransactions()
+	root := models.Account{ID: "0", Type: "ROOT"}
+	for _, act := range []*models.Account{trns[0].Splits[0].Account, trns[0].Splits[1].Account, trns[1].Splits[1].Account} {
+		act.Parent = &root
+		root.Children = append(root.Children, act)
+	}
+	h := BudgetReportHandler{Data: &models.Book{Root: &root, Budgets: testBudgets()}}
+
+	for _, tt := range budgetReportTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var report models.BudgetReport
+		json.NewDecoder(res.Body).Decode(&report)
+		if assert.Equal(t, 2, len(report.Accounts), "number of accounts does not match for %s", tt.path) {
+			assert.Equal(t, "60.00", report.Accounts[0].Periods[0].Actual.String(), "actual amount does not match for %s", tt.path)
+			assert.Equal(t, tt.variance, report.Accounts[1].Total.Variance.String(), "variance does not match for %s", tt.path)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type CashFlowHandler struct {
+	Data *models.Book
+}
+
+func (ch *CashFlowHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	if unsupportedOptions(params, "type", "norecursive", "amount") {
+		httpBadRequest(w, r)
+		return
+	}
+	var opts models.BalanceOptions
+	if err := currencyOption(params, ch.Data, &opts); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	var err error
+	if opts.From, err = dateOption(params, "from"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	if opts.To, err = dateOption(params, "to"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+
+	var accounts []*models.Account
+	if ids := params.Get("accounts"); ids != "" {
+		for _, id := range strings.Split(ids, ",") {
+			act := ch.Data.Account(id)
+			if act == nil {
+				httpNotFound(w, r)
+				return
+			}
+			accounts = append(accounts, act)
+		}
+	}
+
+	resp, err := json.Marshal(ch.Data.CashFlow(accounts, opts))
+	if err != nil {
+		log.Printf("Unable to marshall cash flow to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
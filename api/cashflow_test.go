@@ -0,0 +1,61 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var cashFlowTests = []struct {
+	path    string
+	status  int
+	in      string
+	out     string
+	netFlow string
+}{
+	{"/reports/cash-flow", http.StatusOK, "0", "235.50", "-235.50"},
+	{"/reports/cash-flow?from=2019-01-02&to=2019-01-31", http.StatusOK, "0", "45.50", "-45.50"},
+	{"/reports/cash-flow?accounts=2", http.StatusOK, "60.00", "0", "60.00"},
+	{"/reports/cash-flow?accounts=1,2", http.StatusOK, "0", "175.50", "-175.50"},
+	{"/reports/cash-flow?accounts=1,666", http.StatusNotFound, "", "", ""},
+	{"/reports/cash-flow?to=2019", http.StatusBadRequest, "", "", ""},
+	{"/reports/cash-flow?amount=value", http.StatusBadRequest, "", "", ""},
+}
+
+func TestCashFlowHandler(t *testing.T) {
+	trns := testTransactions()
+	root := models.Account{ID: "0", Type: "ROOT"}
+	for _, act := range []*models.Account{trns[0].Splits[0].Account, trns[0].Splits[1].Account, trns[1].Splits[1].Account} {
+		act.Parent = &root
+		root.Children = append(root.Children, act)
+	}
+	h := CashFlowHandler{Data: &models.Book{Root: &root, Transactions: trns}}
+
+	for _, tt := range cashFlowTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var cf models.CashFlow
+		json.NewDecoder(res.Body).Decode(&cf)
+		assert.Equal(t, tt.in, cf.TotalIn.String(), "money in does not match for %s", tt.path)
+		assert.Equal(t, tt.out, cf.TotalOut.String(), "money out does not match for %s", tt.path)
+		assert.Equal(t, tt.netFlow, cf.NetFlow.String(), "net flow does not match for %s", tt.path)
+	}
+}
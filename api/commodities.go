@@ -0,0 +1,79 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type CommoditiesHandler struct {
+	Data models.Commodities
+}
+
+func (ch *CommoditiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	switch len(path) { // +1 for leading /
+	case 2:
+		ch.serveCommodities(w, r)
+	case 4:
+		space, id := path[2], path[3]
+		if space == "" || id == "" {
+			httpBadRequest(w, r)
+			return
+		}
+		ch.serveCommodity(w, r, space, id)
+	default:
+		httpBadRequest(w, r)
+	}
+}
+
+func (ch *CommoditiesHandler) serveCommodities(w http.ResponseWriter, r *http.Request) {
+	cmdties := ch.Data
+	if space := r.URL.Query().Get("space"); space != "" {
+		cmdties = cmdties.FindBySpace(space)
+	}
+	if cmdties == nil {
+		cmdties = models.Commodities{}
+	}
+
+	resp, err := json.Marshal(cmdties)
+	if err != nil {
+		log.Printf("Unable to marshall commodities to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
+
+func (ch *CommoditiesHandler) serveCommodity(w http.ResponseWriter, r *http.Request, space string, id string) {
+	c := ch.Data.Find(space, id)
+	if c == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(c)
+	if err != nil {
+		log.Printf("Unable to marshall commodity to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
+
+// findCommodity returns the commodity designated by "SPACE:ID" or by an ISO currency code
+func findCommodity(cmdties models.Commodities, name string) *models.Commodity {
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return cmdties.Find(name[:i], name[i+1:])
+	}
+	return cmdties.Find(models.CurrencySpace, name)
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var commoditiesTests = []struct {
+	path   string
+	status int
+	count  int
+}{
+	{"/commodities", http.StatusOK, 3},
+	{"/commodities?space=CURRENCY", http.StatusOK, 2},
+	{"/commodities?space=NOTHING", http.StatusOK, 0},
+}
+
+var commodityTests = []struct {
+	path   string
+	status int
+	name   string
+}{
+	{"/commodities/NASDAQ/AAPL", http.StatusOK, "Apple"},
+	{"/commodities/CURRENCY/EUR", http.StatusOK, ""},
+	{"/commodities/CURRENCY/XXX", http.StatusNotFound, ""},
+	{"/commodities/CURRENCY/", http.StatusBadRequest, ""},
+}
+
+func TestCommoditiesHandler(t *testing.T) {
+	h := CommoditiesHandler{Data: models.Commodities{
+		{Space: "CURRENCY", ID: "EUR"},
+		{Space: "CURRENCY", ID: "USD"},
+		{Space: "NASDAQ", ID: "AAPL", Name: "Apple", Fraction: 1},
+	}}
+
+	for _, tt := range commoditiesTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong.")
+
+		var body []interface{}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tt.count, len(body), "number of results does not match for %s", tt.path)
+	}
+
+	for _, tt := range commodityTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status == http.StatusOK {
+			var c models.Commodity
+			json.NewDecoder(res.Body).Decode(&c)
+			assert.Equal(t, tt.name, c.Name, "commodity name does not match for %s", tt.path)
+		}
+	}
+}
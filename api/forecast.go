@@ -0,0 +1,73 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type ForecastHandler struct {
+	Data *models.Book
+}
+
+func (fh *ForecastHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	if len(path) != 4 || path[3] != "forecast" { // /balance/{:id}/forecast
+		httpBadRequest(w, r)
+		return
+	}
+
+	act := fh.Data.Account(path[2])
+	if act == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	// the forecast starts at date, from and to of the balance have no meaning here
+	params := r.URL.Query()
+	if unsupportedOptions(params, "from", "to") {
+		httpBadRequest(w, r)
+		return
+	}
+	opts, err := balanceOptions(params, fh.Data)
+	if err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	if opts.To, err = dateOption(params, "date"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	until, err := dateOption(params, "until")
+	if err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	interval := params.Get("interval")
+	if interval == "" {
+		interval = models.Month
+	}
+
+	forecast, err := fh.Data.Forecast(act, opts, until, interval)
+	if err != nil {
+		log.Printf("Unable to compute balance forecast: %s\n", err)
+		httpBadRequest(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(forecast)
+	if err != nil {
+		log.Printf("Unable to marshall balance forecast to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
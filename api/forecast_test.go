@@ -0,0 +1,66 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var forecastTests = []struct {
+	path    string
+	status  int
+	entries int
+	periods int
+	lowest  string
+}{
+	{"/balance/1/forecast?date=2019-02-28&until=2019-04-30", http.StatusOK, 2, 3, "-1235.50"},
+	{"/balance/1/forecast?date=2019-02-28&until=2019-04-30&interval=week", http.StatusOK, 2, 10, "-1235.50"},
+	{"/balance/1/forecast?date=2019-02-28", http.StatusOK, 1, 2, "-735.50"},
+	{"/balance/2/forecast?date=2019-02-28&until=2019-04-30", http.StatusOK, 0, 3, "60.00"},
+	{"/balance/1/forecast?date=2019-03-31&until=2019-04-30", http.StatusOK, 2, 2, "-1235.50"},
+	{"/balance/1/forecast?date=2019-02-28&until=2019-01-31", http.StatusBadRequest, 0, 0, ""},
+	{"/balance/1/forecast?until=2019-02-30", http.StatusBadRequest, 0, 0, ""},
+	{"/balance/1/forecast?interval=decade", http.StatusBadRequest, 0, 0, ""},
+	{"/balance/1/forecast?to=2019-02-28", http.StatusBadRequest, 0, 0, ""},
+	{"/balance/1/forecast?from=2019-01-01&date=2019-02-28", http.StatusBadRequest, 0, 0, ""},
+	{"/balance/1/forecast?date=2019-02-28&amount=quantity", http.StatusBadRequest, 0, 0, ""},
+	{"/balance/666/forecast", http.StatusNotFound, 0, 0, ""},
+}
+
+func TestForecastHandler(t *testing.T) {
+	trns := testTransactions()
+	root := models.Account{ID: "0", Type: "ROOT"}
+	for _, act := range []*models.Account{trns[0].Splits[0].Account, trns[0].Splits[1].Account, trns[1].Splits[1].Account} {
+		act.Parent = &root
+		root.Children = append(root.Children, act)
+	}
+	h := ForecastHandler{Data: &models.Book{Root: &root, Scheduled: testScheduled()}}
+
+	for _, tt := range forecastTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var f models.Forecast
+		json.NewDecoder(res.Body).Decode(&f)
+		assert.Equal(t, tt.entries, len(f.Entries), "number of entries does not match for %s", tt.path)
+		assert.Equal(t, tt.periods, len(f.Series), "number of periods does not match for %s", tt.path)
+		assert.Equal(t, tt.lowest, f.Lowest.String(), "lowest balance does not match for %s", tt.path)
+	}
+}
@@ -10,6 +10,32 @@ import (
 func home(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("/accounts\n"))
 	w.Write([]byte("/accounts/{id}\n"))
+	w.Write([]byte("/accounts/{id}/register\n"))
+	w.Write([]byte("/accounts/{id}/tree\n"))
+	w.Write([]byte("/accounts/{id}/lots\n"))
+	w.Write([]byte("/accounts/{id}/ancestors\n"))
+	w.Write([]byte("/accounts/{id}/children\n"))
+	w.Write([]byte("/accounts/{id}/descendants\n"))
+	w.Write([]byte("/accounts/by-path/{path}\n"))
 	w.Write([]byte("/accountypes\n"))
+	w.Write([]byte("/books/slots\n"))
 	w.Write([]byte("/balance/{id}\n"))
+	w.Write([]byte("/balance/{id}/series\n"))
+	w.Write([]byte("/balance/{id}/forecast\n"))
+	w.Write([]byte("/commodities\n"))
+	w.Write([]byte("/commodities/{space}/{id}\n"))
+	w.Write([]byte("/transactions\n"))
+	w.Write([]byte("/transactions/{id}\n"))
+	w.Write([]byte("/status\n"))
+	w.Write([]byte("/scheduled\n"))
+	w.Write([]byte("/scheduled/upcoming\n"))
+	w.Write([]byte("/budgets\n"))
+	w.Write([]byte("/budgets/{id}\n"))
+	w.Write([]byte("/budgets/{id}/report\n"))
+	w.Write([]byte("/reports/balance-sheet\n"))
+	w.Write([]byte("/reports/income-statement\n"))
+	w.Write([]byte("/reports/cash-flow\n"))
+	w.Write([]byte("/reports/trial-balance\n"))
+	w.Write([]byte("/reports/capital-gains\n"))
+	w.Write([]byte("/reports/portfolio\n"))
 }
@@ -25,6 +25,6 @@ func TestHome(t *testing.T) {
 	}
 	res.Body.Close()
 
-	want := "/accounts\n/accounts/{id}\n/accountypes\n/balance/{id}\n"
+	want := "/accounts\n/accounts/{id}\n/accounts/{id}/register\n/accounts/{id}/tree\n/accounts/{id}/lots\n/accounts/{id}/ancestors\n/accounts/{id}/children\n/accounts/{id}/descendants\n/accounts/by-path/{path}\n/accountypes\n/books/slots\n/balance/{id}\n/balance/{id}/series\n/balance/{id}/forecast\n/commodities\n/commodities/{space}/{id}\n/transactions\n/transactions/{id}\n/status\n/scheduled\n/scheduled/upcoming\n/budgets\n/budgets/{id}\n/budgets/{id}/report\n/reports/balance-sheet\n/reports/income-statement\n/reports/cash-flow\n/reports/trial-balance\n/reports/capital-gains\n/reports/portfolio\n"
 	assert.Equal(t, want, string(body), "Response body is wrong.")
 }
@@ -0,0 +1,55 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type IncomeStatementHandler struct {
+	Data *models.Book
+}
+
+func (ih *IncomeStatementHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	if unsupportedOptions(params, "type", "norecursive", "amount") {
+		httpBadRequest(w, r)
+		return
+	}
+	var opts models.BalanceOptions
+	if err := currencyOption(params, ih.Data, &opts); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	var err error
+	if opts.From, err = dateOption(params, "from"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	if opts.To, err = dateOption(params, "to"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+
+	is, err := ih.Data.IncomeStatement(opts, params.Get("compare"))
+	if err != nil {
+		log.Printf("Unable to compute income statement: %s\n", err)
+		httpBadRequest(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(is)
+	if err != nil {
+		log.Printf("Unable to marshall income statement to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var incomeStatementTests = []struct {
+	path      string
+	status    int
+	netIncome string
+	compare   string
+}{
+	{"/reports/income-statement", http.StatusOK, "-235.50", ""},
+	{"/reports/income-statement?from=2019-02-01&to=2019-02-28", http.StatusOK, "-130.00", ""},
+	{"/reports/income-statement?from=2019-02-01&to=2019-02-28&compare=previous-period", http.StatusOK, "-130.00", "-105.50"},
+	{"/reports/income-statement?from=2019-02-01&to=2019-02-28&compare=previous-year", http.StatusOK, "-130.00", "0"},
+	{"/reports/income-statement?compare=previous-period", http.StatusBadRequest, "", ""},
+	{"/reports/income-statement?from=2019-02-01&compare=previous-decade", http.StatusBadRequest, "", ""},
+	{"/reports/income-statement?from=2019-02", http.StatusBadRequest, "", ""},
+	{"/reports/income-statement?type=INCOME", http.StatusBadRequest, "", ""},
+}
+
+func TestIncomeStatementHandler(t *testing.T) {
+	trns := testTransactions()
+	root := models.Account{ID: "0", Type: "ROOT"}
+	for _, act := range []*models.Account{trns[0].Splits[0].Account, trns[0].Splits[1].Account, trns[1].Splits[1].Account} {
+		act.Parent = &root
+		root.Children = append(root.Children, act)
+	}
+	h := IncomeStatementHandler{Data: &models.Book{Root: &root}}
+
+	for _, tt := range incomeStatementTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var is models.IncomeStatement
+		json.NewDecoder(res.Body).Decode(&is)
+		assert.Equal(t, tt.netIncome, is.NetIncome.String(), "net income does not match for %s", tt.path)
+		if tt.compare == "" {
+			assert.Nil(t, is.CompareNetIncome, "unexpected comparison for %s", tt.path)
+		} else if assert.NotNil(t, is.CompareNetIncome, "missing comparison for %s", tt.path) {
+			assert.Equal(t, tt.compare, is.CompareNetIncome.String(), "net income of compared period does not match for %s", tt.path)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+// LotsHandler returns the lots of an account with their cost and their sales
+type LotsHandler struct {
+	Data *models.Book
+}
+
+func (lh *LotsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	if len(path) != 4 || path[3] != "lots" { // /accounts/{:id}/lots
+		httpBadRequest(w, r)
+		return
+	}
+
+	act := lh.Data.Account(path[2])
+	if act == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	params := r.URL.Query()
+	var opts models.BalanceOptions
+	if err := currencyOption(params, lh.Data, &opts); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	var err error
+	if opts.To, err = dateOption(params, "date"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+
+	lots := make([]models.LotStatus, 0, len(act.Lots)+1)
+	for _, l := range act.AllLots() {
+		lots = append(lots, l.Status(opts))
+	}
+
+	resp, err := json.Marshal(lots)
+	if err != nil {
+		log.Printf("Unable to marshall lots to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
+
+type CapitalGainsHandler struct {
+	Data *models.Book
+}
+
+func (ch *CapitalGainsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	var opts models.BalanceOptions
+	if err := currencyOption(params, ch.Data, &opts); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	var err error
+	if opts.From, err = dateOption(params, "from"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	if opts.To, err = dateOption(params, "to"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(ch.Data.CapitalGains(opts))
+	if err != nil {
+		log.Printf("Unable to marshall capital gains to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+// testLots returns a book where 10 shares are bought for 100.00 then 4 are sold for 60.00
+func testLots() *models.Book {
+	usd := &models.Commodity{Space: models.CurrencySpace, ID: "USD", Fraction: 100}
+	xyz := &models.Commodity{Space: "NYSE", ID: "XYZ", Fraction: 1}
+	root := &models.Account{ID: "0", Type: "ROOT"}
+	bank := &models.Account{ID: "1", Name: "Bank", Type: "BANK", Commodity: usd, SCU: 100, Parent: root}
+	stock := &models.Account{ID: "4", Name: "XYZ", Type: "STOCK", Commodity: xyz, SCU: 1, Parent: root}
+	root.Children = []*models.Account{bank, stock}
+	lot := &models.Lot{ID: "l1", Title: "Lot 1", Account: stock}
+	stock.Lots = []*models.Lot{lot}
+
+	link := func(t *models.Transaction, act *models.Account, value int64, quantity int64) {
+		s := &models.Split{Transaction: t, Account: act, Value: models.NewAmount(value, 100), Quantity: models.NewAmount(quantity, 1)}
+		if act == stock {
+			s.Lot = lot
+			lot.Splits = append(lot.Splits, s)
+		}
+		t.Splits = append(t.Splits, s)
+		act.Splits = append(act.Splits, s)
+	}
+	buy := &models.Transaction{ID: "t1", DatePosted: "2019-01-01", Currency: usd}
+	link(buy, bank, -10000, -100)
+	link(buy, stock, 10000, 10)
+	sell := &models.Transaction{ID: "t2", DatePosted: "2019-02-01", Currency: usd}
+	link(sell, stock, -6000, -4)
+	link(sell, bank, 6000, 60)
+
+	prices := []*models.Price{{Commodity: xyz, Currency: usd, Date: "2019-02-01", Value: models.NewAmount(15, 1)}}
+	return &models.Book{Root: root, Commodities: models.Commodities{usd, xyz}, Prices: models.NewPriceDB(prices)}
+}
+
+var lotsTests = []struct {
+	path     string
+	status   int
+	count    int
+	quantity string
+	realized string
+}{
+	{"/accounts/4/lots?date=2019-03-31", http.StatusOK, 1, "6", "20.00"},
+	{"/accounts/4/lots?date=2019-01-15", http.StatusOK, 1, "10", "0"},
+	{"/accounts/1/lots", http.StatusOK, 0, "", ""},
+	{"/accounts/4/lots?date=2019-02-30", http.StatusBadRequest, 0, "", ""},
+	{"/accounts/4/lots?currency=XXX", http.StatusBadRequest, 0, "", ""},
+	{"/accounts/666/lots", http.StatusNotFound, 0, "", ""},
+}
+
+func TestLotsHandler(t *testing.T) {
+	h := LotsHandler{Data: testLots()}
+
+	for _, tt := range lotsTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var lots []models.LotStatus
+		json.NewDecoder(res.Body).Decode(&lots)
+		if assert.Equal(t, tt.count, len(lots), "number of lots does not match for %s", tt.path) && tt.count > 0 {
+			assert.Equal(t, tt.quantity, lots[0].Quantity.String(), "quantity does not match for %s", tt.path)
+			assert.Equal(t, tt.realized, lots[0].Realized.String(), "realized gain does not match for %s", tt.path)
+		}
+	}
+}
+
+func TestLotsHandlerUnassigned(t *testing.T) {
+	// the same shares without lots in GnuCash are returned as a single lot, like in the capital gains report
+	book := testLots()
+	stock := book.Account("4")
+	stock.Lots = nil
+	for _, s := range stock.Splits {
+		s.Lot = nil
+	}
+	h := LotsHandler{Data: book}
+
+	req, err := http.NewRequest("GET", "/accounts/4/lots?date=2019-03-31", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	res := w.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode, "Status code is wrong")
+	var lots []models.LotStatus
+	json.NewDecoder(res.Body).Decode(&lots)
+	if assert.Equal(t, 1, len(lots), "splits not assigned to a lot should be returned as a lot") {
+		assert.Empty(t, lots[0].ID, "ID of the lot of unassigned splits should be empty")
+		assert.Equal(t, "6", lots[0].Quantity.String(), "quantity does not match")
+		assert.Equal(t, "20.00", lots[0].Realized.String(), "realized gain does not match")
+	}
+
+	cg := book.CapitalGains(models.BalanceOptions{To: "2019-03-31"})
+	assert.Equal(t, "20.00", cg.TotalRealized.String(), "realized gains do not match")
+}
+
+var capitalGainsTests = []struct {
+	path       string
+	status     int
+	realized   string
+	unrealized string
+}{
+	{"/reports/capital-gains?to=2019-03-31", http.StatusOK, "20.00", "30.00"},
+	{"/reports/capital-gains?from=2019-03-01&to=2019-03-31", http.StatusOK, "0", "30.00"},
+	{"/reports/capital-gains?to=2019-01-15", http.StatusOK, "0", "0"},
+	{"/reports/capital-gains?from=2019", http.StatusBadRequest, "", ""},
+	{"/reports/capital-gains?currency=XXX", http.StatusBadRequest, "", ""},
+}
+
+func TestCapitalGainsHandler(t *testing.T) {
+	h := CapitalGainsHandler{Data: testLots()}
+
+	for _, tt := range capitalGainsTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var cg models.CapitalGains
+		json.NewDecoder(res.Body).Decode(&cg)
+		assert.Equal(t, tt.realized, cg.TotalRealized.String(), "realized gains do not match for %s", tt.path)
+		assert.Equal(t, tt.unrealized, cg.TotalUnrealized.String(), "unrealized gains do not match for %s", tt.path)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type PortfolioHandler struct {
+	Data *models.Book
+}
+
+func (ph *PortfolioHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	var opts models.BalanceOptions
+	if err := currencyOption(params, ph.Data, &opts); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	var err error
+	if opts.From, err = dateOption(params, "from"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	if opts.To, err = dateOption(params, "date"); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	if opts.From != "" && opts.To != "" && opts.To < opts.From {
+		httpBadRequest(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(ph.Data.Portfolio(opts))
+	if err != nil {
+		log.Printf("Unable to marshall portfolio to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var portfolioTests = []struct {
+	path     string
+	status   int
+	holdings int
+	value    string
+	gain     string
+}{
+	{"/reports/portfolio?date=2019-03-31&currency=USD", http.StatusOK, 1, "90.00", "30.00"},
+	{"/reports/portfolio?from=2019-02-01&date=2019-03-31&currency=USD", http.StatusOK, 1, "90.00", "30.00"},
+	{"/reports/portfolio?date=2018-12-31&currency=USD", http.StatusOK, 0, "0.00", "0.00"},
+	{"/reports/portfolio?from=2019-03-31&date=2019-01-01", http.StatusBadRequest, 0, "", ""},
+	{"/reports/portfolio?date=2019", http.StatusBadRequest, 0, "", ""},
+	{"/reports/portfolio?currency=XXX", http.StatusBadRequest, 0, "", ""},
+}
+
+func TestPortfolioHandler(t *testing.T) {
+	h := PortfolioHandler{Data: testLots()}
+
+	for _, tt := range portfolioTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var pf models.Portfolio
+		json.NewDecoder(res.Body).Decode(&pf)
+		assert.Equal(t, tt.holdings, len(pf.Holdings), "number of holdings does not match for %s", tt.path)
+		assert.Equal(t, tt.value, pf.Value.String(), "value does not match for %s", tt.path)
+		assert.Equal(t, tt.gain, pf.Gain.String(), "gain does not match for %s", tt.path)
+	}
+}
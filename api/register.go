@@ -0,0 +1,54 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type RegisterHandler struct {
+	Data *models.Book
+}
+
+func (rh *RegisterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	if len(path) != 4 || path[3] != "register" { // /accounts/{:id}/register
+		httpBadRequest(w, r)
+		return
+	}
+
+	act := rh.Data.Account(path[2])
+	if act == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	// amounts of the register are not converted
+	params := r.URL.Query()
+	if _, ok := params["currency"]; ok {
+		httpBadRequest(w, r)
+		return
+	}
+	opts, err := balanceOptions(params, rh.Data)
+	if err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	entries := act.Register(opts)
+
+	resp, err := json.Marshal(entries)
+	if err != nil {
+		log.Printf("Unable to marshall register to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
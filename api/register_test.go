@@ -0,0 +1,61 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var registerTests = []struct {
+	path    string
+	status  int
+	count   int
+	balance string
+}{
+	{"/accounts/1/register", http.StatusOK, 3, "-235.50"},
+	{"/accounts/1/register?from=2019-01-02&to=2019-01-31", http.StatusOK, 1, "-105.50"},
+	{"/accounts/0/register", http.StatusOK, 6, "0.00"},
+	{"/accounts/0/register?norecursive", http.StatusOK, 0, ""},
+	{"/accounts/1/register?currency=USD", http.StatusBadRequest, 0, ""},
+	{"/accounts/666/register", http.StatusNotFound, 0, ""},
+	{"/accounts/1/journal", http.StatusBadRequest, 0, ""},
+}
+
+func TestRegisterHandler(t *testing.T) {
+	trns := testTransactions()
+	root := models.Account{ID: "0", Type: "ROOT"}
+	for _, act := range []*models.Account{trns[0].Splits[0].Account, trns[0].Splits[1].Account, trns[1].Splits[1].Account} {
+		act.Parent = &root
+		root.Children = append(root.Children, act)
+	}
+	usd := &models.Commodity{Space: models.CurrencySpace, ID: "USD", Fraction: 100}
+	h := RegisterHandler{Data: &models.Book{Root: &root, Commodities: models.Commodities{usd}}}
+
+	for _, tt := range registerTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var entries []models.RegisterEntry
+		json.NewDecoder(res.Body).Decode(&entries)
+		if assert.Equal(t, tt.count, len(entries), "number of entries does not match for %s", tt.path) && tt.count > 0 {
+			assert.Equal(t, tt.balance, entries[len(entries)-1].Balance.String(), "running balance does not match for %s", tt.path)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+// RelativesHandler returns the ancestors, the children or the descendants of an account
+type RelativesHandler struct {
+	Data *models.Book
+}
+
+func (rh *RelativesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	if len(path) != 4 { // /accounts/{:id}/{:relation}
+		httpBadRequest(w, r)
+		return
+	}
+
+	act := rh.Data.Account(path[2])
+	if act == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	var relatives []*models.Account
+	switch path[3] {
+	case "ancestors":
+		relatives = act.Ancestors()
+	case "children":
+		relatives = act.Children
+	case "descendants":
+		relatives = act.Descendants()
+	default:
+		httpBadRequest(w, r)
+		return
+	}
+
+	acts := make([]*models.Account, 0, len(relatives))
+	atype := r.URL.Query().Get("type")
+	for _, a := range relatives {
+		if atype == "" || a.Type == atype {
+			acts = append(acts, a)
+		}
+	}
+
+	resp, err := json.Marshal(acts)
+	if err != nil {
+		log.Printf("Unable to marshall accounts to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
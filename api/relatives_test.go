@@ -0,0 +1,67 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var relativesTests = []struct {
+	path   string
+	status int
+	ids    []string
+}{
+	{"/accounts/3/ancestors", http.StatusOK, []string{"0", "1", "2"}},
+	{"/accounts/3/ancestors?type=EXPENSE", http.StatusOK, []string{"1", "2"}},
+	{"/accounts/0/ancestors", http.StatusOK, []string{}},
+	{"/accounts/1/children", http.StatusOK, []string{"2", "4"}},
+	{"/accounts/3/children", http.StatusOK, []string{}},
+	{"/accounts/1/descendants", http.StatusOK, []string{"2", "4", "3", "5"}},
+	{"/accounts/0/descendants?type=ASSET", http.StatusOK, []string{"6"}},
+	{"/accounts/666/children", http.StatusNotFound, nil},
+	{"/accounts/1/cousins", http.StatusBadRequest, nil},
+}
+
+func TestRelativesHandler(t *testing.T) {
+	root := &models.Account{ID: "0", Name: "Root Account", Type: "ROOT"}
+	expenses := &models.Account{ID: "1", Name: "Expenses", Type: "EXPENSE", Parent: root}
+	auto := &models.Account{ID: "2", Name: "Auto", Type: "EXPENSE", Parent: expenses}
+	home := &models.Account{ID: "4", Name: "Home", Type: "EXPENSE", Parent: expenses}
+	root.Children = []*models.Account{expenses, {ID: "6", Name: "Assets", Type: "ASSET", Parent: root}}
+	expenses.Children = []*models.Account{auto, home}
+	auto.Children = []*models.Account{{ID: "3", Name: "Fuel", Type: "EXPENSE", Parent: auto}}
+	home.Children = []*models.Account{{ID: "5", Name: "Fuel", Type: "EXPENSE", Parent: home}}
+	h := RelativesHandler{Data: &models.Book{Root: root}}
+
+	for _, tt := range relativesTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var acts []struct {
+			ID string `json:"id"`
+		}
+		json.NewDecoder(res.Body).Decode(&acts)
+		ids := make([]string, 0)
+		for _, a := range acts {
+			ids = append(ids, a.ID)
+		}
+		assert.Equal(t, tt.ids, ids, "accounts do not match for %s", tt.path)
+	}
+}
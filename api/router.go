@@ -13,12 +13,12 @@ import (
 
 // Router will send incoming requests to dedicated handler
 type Router struct {
-	root *models.Account
+	store *models.Store
 }
 
 // NewRouter returns a new Router instance
-func NewRouter(root *models.Account) *Router {
-	return &Router{root: root}
+func NewRouter(store *models.Store) *Router {
+	return &Router{store: store}
 }
 
 func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -35,14 +35,39 @@ func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// all handlers of a request work on the same book
+	book := router.store.Snapshot()
+
 	// main routing
 	path := strings.Split(r.URL.Path, "/")
 	switch path[1] {
 	case "accounts":
+		if len(path) > 3 && path[2] == "by-path" { // /accounts/by-path/{:path}
+			h := AccountsHandler{Data: book}
+			h.ServeHTTP(w, r)
+			return
+		}
 		switch len(path) {
 		case 2, 3: // /accounts or /accounts/{:id}
-			h := AccountsHandler{Data: router.root}
+			h := AccountsHandler{Data: book}
 			h.ServeHTTP(w, r)
+		case 4: // /accounts/{:id}/register, /accounts/{:id}/tree, /accounts/{:id}/lots or /accounts/{:id}/{:relation}
+			switch path[3] {
+			case "register":
+				h := RegisterHandler{Data: book}
+				h.ServeHTTP(w, r)
+			case "tree":
+				h := TreeHandler{Data: book}
+				h.ServeHTTP(w, r)
+			case "lots":
+				h := LotsHandler{Data: book}
+				h.ServeHTTP(w, r)
+			case "ancestors", "children", "descendants":
+				h := RelativesHandler{Data: book}
+				h.ServeHTTP(w, r)
+			default:
+				httpBadRequest(w, r)
+			}
 		default:
 			httpBadRequest(w, r)
 		}
@@ -50,7 +75,34 @@ func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "accounttypes":
 		switch len(path) {
 		case 2: // /accounttypes
-			h := AccountTypesHandler{Data: router.root}
+			h := AccountTypesHandler{Data: book}
+			h.ServeHTTP(w, r)
+		default:
+			httpBadRequest(w, r)
+		}
+		return
+	case "commodities":
+		switch len(path) {
+		case 2, 4: // /commodities or /commodities/{:space}/{:id}
+			h := CommoditiesHandler{Data: book.Commodities}
+			h.ServeHTTP(w, r)
+		default:
+			httpBadRequest(w, r)
+		}
+		return
+	case "transactions":
+		switch len(path) {
+		case 2, 3: // /transactions or /transactions/{:id}
+			h := TransactionsHandler{Data: book.Transactions}
+			h.ServeHTTP(w, r)
+		default:
+			httpBadRequest(w, r)
+		}
+		return
+	case "status":
+		switch len(path) {
+		case 2: // /status
+			h := StatusHandler{Data: router.store.Status()}
 			h.ServeHTTP(w, r)
 		default:
 			httpBadRequest(w, r)
@@ -59,8 +111,86 @@ func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "balance":
 		switch len(path) {
 		case 3: // /balance/{:id}
-			h := BalanceHandler{Data: router.root}
+			h := BalanceHandler{Data: book}
 			h.ServeHTTP(w, r)
+		case 4: // /balance/{:id}/series or /balance/{:id}/forecast
+			switch path[3] {
+			case "series":
+				h := SeriesHandler{Data: book}
+				h.ServeHTTP(w, r)
+			case "forecast":
+				h := ForecastHandler{Data: book}
+				h.ServeHTTP(w, r)
+			default:
+				httpBadRequest(w, r)
+			}
+		default:
+			httpBadRequest(w, r)
+		}
+		return
+	case "books":
+		switch len(path) {
+		case 3: // /books/slots
+			h := BookSlotsHandler{Data: book.Slots}
+			h.ServeHTTP(w, r)
+		default:
+			httpBadRequest(w, r)
+		}
+		return
+	case "budgets":
+		switch len(path) {
+		case 2, 3: // /budgets or /budgets/{:id}
+			h := BudgetsHandler{Data: book.Budgets}
+			h.ServeHTTP(w, r)
+		case 4: // /budgets/{:id}/report
+			h := BudgetReportHandler{Data: book}
+			h.ServeHTTP(w, r)
+		default:
+			httpBadRequest(w, r)
+		}
+		return
+	case "scheduled":
+		switch len(path) {
+		case 2: // /scheduled
+			h := ScheduledHandler{Data: book.Scheduled}
+			h.ServeHTTP(w, r)
+		case 3: // /scheduled/upcoming
+			switch path[2] {
+			case "upcoming":
+				h := UpcomingHandler{Data: book}
+				h.ServeHTTP(w, r)
+			default:
+				httpNotFound(w, r)
+			}
+		default:
+			httpBadRequest(w, r)
+		}
+		return
+	case "reports":
+		switch len(path) {
+		case 3: // /reports/{:name}
+			switch path[2] {
+			case "balance-sheet":
+				h := BalanceSheetHandler{Data: book}
+				h.ServeHTTP(w, r)
+			case "income-statement":
+				h := IncomeStatementHandler{Data: book}
+				h.ServeHTTP(w, r)
+			case "cash-flow":
+				h := CashFlowHandler{Data: book}
+				h.ServeHTTP(w, r)
+			case "trial-balance":
+				h := TrialBalanceHandler{Data: book}
+				h.ServeHTTP(w, r)
+			case "capital-gains":
+				h := CapitalGainsHandler{Data: book}
+				h.ServeHTTP(w, r)
+			case "portfolio":
+				h := PortfolioHandler{Data: book}
+				h.ServeHTTP(w, r)
+			default:
+				httpNotFound(w, r)
+			}
 		default:
 			httpBadRequest(w, r)
 		}
@@ -21,16 +21,53 @@ var routesToTest = []struct {
 	{"GET", "/", http.StatusOK},
 	{"GET", "/accounts", http.StatusOK},
 	{"GET", "/accounts/0", http.StatusOK},
+	{"GET", "/accounts/0/register", http.StatusOK},
+	{"GET", "/accounts/0/tree", http.StatusOK},
+	{"GET", "/accounts/0/lots", http.StatusOK},
+	{"GET", "/accounts/0/ancestors", http.StatusOK},
+	{"GET", "/accounts/0/children", http.StatusOK},
+	{"GET", "/accounts/0/descendants", http.StatusOK},
+	{"GET", "/accounts/by-path/Current%20Assets", http.StatusOK},
 	{"GET", "/accounttypes", http.StatusOK},
 	{"GET", "/balance/0", http.StatusOK},
+	{"GET", "/balance/0/series", http.StatusOK},
+	{"GET", "/balance/0/forecast", http.StatusOK},
+	{"GET", "/commodities", http.StatusOK},
+	{"GET", "/commodities/CURRENCY/EUR", http.StatusOK},
+	{"GET", "/transactions", http.StatusOK},
+	{"GET", "/status", http.StatusOK},
+	{"GET", "/books/slots", http.StatusOK},
+	{"GET", "/scheduled", http.StatusOK},
+	{"GET", "/budgets", http.StatusOK},
+	{"GET", "/budgets/b1", http.StatusNotFound},
+	{"GET", "/budgets/b1/report", http.StatusNotFound},
+	{"GET", "/scheduled/upcoming", http.StatusOK},
+	{"GET", "/reports/balance-sheet", http.StatusOK},
+	{"GET", "/reports/income-statement", http.StatusOK},
+	{"GET", "/reports/cash-flow", http.StatusOK},
+	{"GET", "/reports/trial-balance", http.StatusOK},
+	{"GET", "/reports/capital-gains", http.StatusOK},
+	{"GET", "/reports/portfolio", http.StatusOK},
 	// Not Found
 	{"GET", "/not-exists", http.StatusNotFound},
+	{"GET", "/accounts/by-path/Assets:Not%20Exists", http.StatusNotFound},
+	{"GET", "/reports/not-exists", http.StatusNotFound},
+	{"GET", "/scheduled/past", http.StatusNotFound},
 	// Not Allowed
 	{"POST", "/", http.StatusMethodNotAllowed},
 	// Bad Request
 	{"GET", "/accounts/0/1", http.StatusBadRequest},
 	{"GET", "/accounttypes/0", http.StatusBadRequest},
 	{"GET", "/balance", http.StatusBadRequest},
+	{"GET", "/balance/0/flow", http.StatusBadRequest},
+	{"GET", "/commodities/CURRENCY", http.StatusBadRequest},
+	{"GET", "/transactions/0/1", http.StatusBadRequest},
+	{"GET", "/status/0", http.StatusBadRequest},
+	{"GET", "/reports", http.StatusBadRequest},
+	{"GET", "/scheduled/upcoming/0", http.StatusBadRequest},
+	{"GET", "/budgets/b1/report/0", http.StatusBadRequest},
+	{"GET", "/books", http.StatusBadRequest},
+	{"GET", "/books/options", http.StatusBadRequest},
 }
 
 func TestRoutes(t *testing.T) {
@@ -40,7 +77,12 @@ func TestRoutes(t *testing.T) {
 		Name: "Dummy",
 		Type: "ROOT",
 	}
-	r := NewRouter(&root)
+	root.Children = []*models.Account{{ID: "1", Name: "Current Assets", Type: "ASSET", Parent: &root}}
+	book := models.Book{
+		Root:        &root,
+		Commodities: models.Commodities{{Space: "CURRENCY", ID: "EUR"}},
+	}
+	r := NewRouter(models.NewStore(&book))
 	ts := httptest.NewServer(r)
 	defer ts.Close()
 
@@ -0,0 +1,64 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type ScheduledHandler struct {
+	Data []*models.ScheduledTransaction
+}
+
+func (sh *ScheduledHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	scheduled := sh.Data
+	if scheduled == nil {
+		scheduled = make([]*models.ScheduledTransaction, 0)
+	}
+
+	resp, err := json.Marshal(scheduled)
+	if err != nil {
+		log.Printf("Unable to marshall scheduled transactions to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
+
+type UpcomingHandler struct {
+	Data *models.Book
+}
+
+func (uh *UpcomingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	from, err := dateOption(params, "from")
+	if err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	until, err := dateOption(params, "until")
+	if err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	if from != "" && until != "" && until < from {
+		httpBadRequest(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(uh.Data.Upcoming(from, until))
+	if err != nil {
+		log.Printf("Unable to marshall upcoming transactions to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
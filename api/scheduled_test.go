@@ -0,0 +1,99 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+func testScheduled() []*models.ScheduledTransaction {
+	return []*models.ScheduledTransaction{
+		{
+			ID:          "sx1",
+			Name:        "Rent",
+			Enabled:     true,
+			Start:       "2019-01-01",
+			Last:        "2019-02-05",
+			Recurrences: []models.Recurrence{{Period: models.Monthly, Multiplier: 1, Start: "2019-01-05"}},
+			Templates: []models.ScheduledTemplate{{
+				Description: "Rent",
+				Splits: []models.ScheduledSplit{
+					{Account: models.AccountRef{ID: "1", Name: "Bank"}, Amount: models.NewAmount(-50000, 100)},
+					{Account: models.AccountRef{ID: "4", Name: "Rent"}, Amount: models.NewAmount(50000, 100)},
+				},
+			}},
+		},
+		{
+			ID:          "sx2",
+			Name:        "Insurance",
+			Enabled:     true,
+			Start:       "2019-01-01",
+			Recurrences: []models.Recurrence{{Period: models.Yearly, Multiplier: 1, Start: "2019-03-20"}},
+			Templates:   []models.ScheduledTemplate{{Description: "Insurance"}},
+		},
+	}
+}
+
+func TestScheduledHandler(t *testing.T) {
+	h := ScheduledHandler{Data: testScheduled()}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/scheduled", nil))
+	var scheduled []models.ScheduledTransaction
+	json.NewDecoder(w.Result().Body).Decode(&scheduled)
+	if assert.Equal(t, 2, len(scheduled), "Problem with the number of scheduled transactions") {
+		assert.Equal(t, "Rent", scheduled[0].Name, "Problem with name of scheduled transaction")
+		assert.Equal(t, "-500.00", scheduled[0].Templates[0].Splits[0].Amount.String(), "Problem with amount of template split")
+	}
+
+	h = ScheduledHandler{}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/scheduled", nil))
+	assert.Equal(t, "[]", w.Body.String(), "Response body is wrong for a book without scheduled transactions")
+}
+
+var upcomingTests = []struct {
+	path   string
+	status int
+	dates  []string
+}{
+	{"/scheduled/upcoming?from=2019-03-01&until=2019-03-31", http.StatusOK, []string{"2019-03-05", "2019-03-20"}},
+	{"/scheduled/upcoming?from=2019-03-06&until=2019-04-30", http.StatusOK, []string{"2019-03-20", "2019-04-05"}},
+	{"/scheduled/upcoming?from=2019-03-01", http.StatusOK, []string{"2019-03-05", "2019-03-20"}},
+	{"/scheduled/upcoming?from=2019-03-06&until=2019-03-10", http.StatusOK, []string{}},
+	{"/scheduled/upcoming?from=2019-03-31&until=2019-03-01", http.StatusBadRequest, nil},
+	{"/scheduled/upcoming?until=2019-13-01", http.StatusBadRequest, nil},
+}
+
+func TestUpcomingHandler(t *testing.T) {
+	h := UpcomingHandler{Data: &models.Book{Scheduled: testScheduled()}}
+
+	for _, tt := range upcomingTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var occurrences []models.Occurrence
+		json.NewDecoder(res.Body).Decode(&occurrences)
+		dates := make([]string, 0)
+		for _, o := range occurrences {
+			dates = append(dates, o.Date)
+		}
+		assert.Equal(t, tt.dates, dates, "Dates of occurrences do not match for %s", tt.path)
+	}
+}
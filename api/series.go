@@ -0,0 +1,68 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type SeriesHandler struct {
+	Data *models.Book
+}
+
+func (sh *SeriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	if len(path) != 4 || path[3] != "series" { // /balance/{:id}/series
+		httpBadRequest(w, r)
+		return
+	}
+
+	act := sh.Data.Account(path[2])
+	if act == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	params := r.URL.Query()
+	opts, err := balanceOptions(params, sh.Data)
+	if err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	interval := params.Get("interval")
+	if interval == "" {
+		interval = models.Month
+	}
+	var flow bool
+	switch params.Get("mode") {
+	case "", "balance":
+	case "flow":
+		flow = true
+	default:
+		httpBadRequest(w, r)
+		return
+	}
+
+	series, err := act.BalanceSeries(opts, interval, flow)
+	if err != nil {
+		log.Printf("Unable to compute balance series: %s\n", err)
+		httpBadRequest(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(series)
+	if err != nil {
+		log.Printf("Unable to marshall balance series to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
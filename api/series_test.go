@@ -0,0 +1,62 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var seriesTests = []struct {
+	path   string
+	status int
+	count  int
+	last   string
+}{
+	{"/balance/1/series?to=2019-02-28", http.StatusOK, 2, "-235.50"},
+	{"/balance/1/series?from=2019-01-01&to=2019-03-31&interval=quarter", http.StatusOK, 1, "-235.50"},
+	{"/balance/1/series?from=2019-01-01&to=2019-01-31&interval=week", http.StatusOK, 5, "-105.50"},
+	{"/balance/1/series?from=2019-02-01&to=2019-02-28&mode=flow", http.StatusOK, 1, "-130.00"},
+	{"/balance/0/series?from=2019-01-01&to=2019-02-28&interval=month", http.StatusOK, 2, "0.00"},
+	{"/balance/1/series?interval=decade", http.StatusBadRequest, 0, ""},
+	{"/balance/1/series?mode=delta", http.StatusBadRequest, 0, ""},
+	{"/balance/1/series?from=2019-03-01&to=2019-01-01", http.StatusBadRequest, 0, ""},
+	{"/balance/666/series", http.StatusNotFound, 0, ""},
+}
+
+func TestSeriesHandler(t *testing.T) {
+	trns := testTransactions()
+	root := models.Account{ID: "0", Type: "ROOT"}
+	for _, act := range []*models.Account{trns[0].Splits[0].Account, trns[0].Splits[1].Account, trns[1].Splits[1].Account} {
+		act.Parent = &root
+		root.Children = append(root.Children, act)
+	}
+	h := SeriesHandler{Data: &models.Book{Root: &root}}
+
+	for _, tt := range seriesTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var series []models.Balance
+		json.NewDecoder(res.Body).Decode(&series)
+		if assert.Equal(t, tt.count, len(series), "number of periods does not match for %s", tt.path) && tt.count > 0 {
+			assert.Equal(t, tt.last, series[len(series)-1].Amount.String(), "last balance does not match for %s", tt.path)
+		}
+	}
+}
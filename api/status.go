@@ -0,0 +1,28 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type StatusHandler struct {
+	Data models.Status
+}
+
+func (sh *StatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resp, err := json.Marshal(sh.Data)
+	if err != nil {
+		log.Printf("Unable to marshall status to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+func TestStatusHandler(t *testing.T) {
+	loadedAt := time.Date(2019, 6, 22, 10, 0, 0, 0, time.UTC)
+	h := StatusHandler{Data: models.Status{
+		File:     models.FileInfo{Path: "book.gnucash", Size: 42},
+		LoadedAt: loadedAt,
+		Status:   models.StatusOK,
+	}}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, nil)
+
+	res := w.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode, "Status code is wrong.")
+
+	var body map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&body)
+	assert.Equal(t, "ok", body["status"], "status does not match")
+	assert.Equal(t, "2019-06-22T10:00:00Z", body["loaded_at"], "load time does not match")
+	assert.Nil(t, body["error"], "no error should be reported")
+}
+
+func TestRouterReload(t *testing.T) {
+	book1 := models.Book{Root: &models.Account{ID: "0", Name: "Root Account", Type: "ROOT"}}
+	book2 := models.Book{Root: &models.Account{ID: "1", Name: "Root Account", Type: "ROOT"}}
+	store := models.NewStore(&book1)
+	ts := httptest.NewServer(NewRouter(store))
+	defer ts.Close()
+
+	getStatus := func(path string) int {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, getStatus("/accounts/0"), "Account of first book should be found")
+	store.Replace(&book2)
+	assert.Equal(t, http.StatusNotFound, getStatus("/accounts/0"), "Account of first book should not be found after reload")
+	assert.Equal(t, http.StatusOK, getStatus("/accounts/1"), "Account of second book should be found after reload")
+
+	store.SetError(errors.New("XML syntax error"))
+	assert.Equal(t, http.StatusOK, getStatus("/accounts/1"), "Second book should be kept on reload error")
+
+	res, err := http.Get(ts.URL + "/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var status models.Status
+	json.NewDecoder(res.Body).Decode(&status)
+	assert.Equal(t, models.StatusError, status.Status, "status does not match")
+	assert.Equal(t, "XML syntax error", status.Error, "error does not match")
+}
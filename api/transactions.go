@@ -0,0 +1,87 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type TransactionsHandler struct {
+	Data models.Transactions
+}
+
+func (th *TransactionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	switch len(path) { // +1 for leading /
+	case 2:
+		th.serveTransactions(w, r)
+	case 3:
+		id := path[2]
+		if id == "" {
+			httpBadRequest(w, r)
+			return
+		}
+		th.serveTransactionByID(w, r, id)
+	default:
+		httpBadRequest(w, r)
+	}
+}
+
+func (th *TransactionsHandler) serveTransactions(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	opts := models.TransactionOptions{
+		Account:     params.Get("account"),
+		From:        params.Get("from"),
+		To:          params.Get("to"),
+		Description: params.Get("description"),
+	}
+	if min := params.Get("min"); min != "" {
+		a, err := models.ParseAmount(min)
+		if err != nil {
+			httpBadRequest(w, r)
+			return
+		}
+		opts.Min = &a
+	}
+	if max := params.Get("max"); max != "" {
+		a, err := models.ParseAmount(max)
+		if err != nil {
+			httpBadRequest(w, r)
+			return
+		}
+		opts.Max = &a
+	}
+
+	resp, err := json.Marshal(th.Data.Filter(opts))
+	if err != nil {
+		log.Printf("Unable to marshall transactions to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
+
+func (th *TransactionsHandler) serveTransactionByID(w http.ResponseWriter, r *http.Request, id string) {
+	trn := th.Data.FindByID(id)
+	if trn == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	resp, err := json.Marshal(trn)
+	if err != nil {
+		log.Printf("Unable to marshall transaction to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var transactionsTests = []struct {
+	path   string
+	status int
+	count  int
+}{
+	{"/transactions", http.StatusOK, 3},
+	{"/transactions?account=2", http.StatusOK, 1},
+	{"/transactions?from=2019-01-02&to=2019-01-31", http.StatusOK, 1},
+	{"/transactions?description=market", http.StatusOK, 2},
+	{"/transactions?min=50&max=100.00", http.StatusOK, 1},
+	{"/transactions?min=abc", http.StatusBadRequest, 0},
+	{"/transactions?max=1/0", http.StatusBadRequest, 0},
+}
+
+var transactionByIDTests = []struct {
+	path   string
+	status int
+}{
+	{"/transactions/t1", http.StatusOK},
+	{"/transactions/t666", http.StatusNotFound},
+	{"/transactions/", http.StatusBadRequest},
+}
+
+func testTransactions() models.Transactions {
+	bank := &models.Account{ID: "1", Name: "Bank", Type: "BANK"}
+	fuel := &models.Account{ID: "2", Name: "Fuel", Type: "EXPENSE"}
+	food := &models.Account{ID: "3", Name: "Food", Type: "EXPENSE"}
+
+	trns := models.Transactions{
+		{ID: "t1", DatePosted: "2019-01-01", Description: "Gas station"},
+		{ID: "t2", DatePosted: "2019-01-10", Description: "Supermarket"},
+		{ID: "t3", DatePosted: "2019-02-03", Description: "Supermarket"},
+	}
+	link := func(t *models.Transaction, act *models.Account, value int64) {
+		s := &models.Split{ID: t.ID + act.ID, Transaction: t, Account: act, Value: models.NewAmount(value, 100)}
+		t.Splits = append(t.Splits, s)
+		act.Splits = append(act.Splits, s)
+	}
+	link(trns[0], bank, -6000)
+	link(trns[0], fuel, 6000)
+	link(trns[1], bank, -4550)
+	link(trns[1], food, 4550)
+	link(trns[2], bank, -13000)
+	link(trns[2], food, 13000)
+	return trns
+}
+
+func TestTransactionsHandler(t *testing.T) {
+	h := TransactionsHandler{Data: testTransactions()}
+
+	for _, tt := range transactionsTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+
+		var body []interface{}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tt.count, len(body), "number of results does not match for %s", tt.path)
+	}
+
+	for _, tt := range transactionByIDTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type TreeHandler struct {
+	Data *models.Book
+}
+
+func (th *TreeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(r.URL.Path, "/")
+	if len(path) != 4 || path[3] != "tree" { // /accounts/{:id}/tree
+		httpBadRequest(w, r)
+		return
+	}
+
+	act := th.Data.Account(path[2])
+	if act == nil {
+		httpNotFound(w, r)
+		return
+	}
+
+	params := r.URL.Query()
+	depth := -1
+	if d := params.Get("depth"); d != "" {
+		var err error
+		depth, err = strconv.Atoi(d)
+		if err != nil || depth < 0 {
+			httpBadRequest(w, r)
+			return
+		}
+	}
+	var opts *models.BalanceOptions
+	if _, ok := params["balance"]; ok {
+		o, err := balanceOptions(params, th.Data)
+		if err != nil {
+			httpBadRequest(w, r)
+			return
+		}
+		opts = &o
+	}
+
+	resp, err := json.Marshal(act.Tree(depth, opts))
+	if err != nil {
+		log.Printf("Unable to marshall accounts tree to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
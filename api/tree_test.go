@@ -0,0 +1,71 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var treeTests = []struct {
+	path     string
+	status   int
+	children int
+	balance  string
+}{
+	{"/accounts/0/tree", http.StatusOK, 3, ""},
+	{"/accounts/0/tree?depth=0", http.StatusOK, 0, ""},
+	{"/accounts/0/tree?balance", http.StatusOK, 3, "0.00"},
+	{"/accounts/1/tree?balance&to=2019-01-31", http.StatusOK, 0, "-105.50"},
+	{"/accounts/0/tree?depth=-1", http.StatusBadRequest, 0, ""},
+	{"/accounts/0/tree?depth=all", http.StatusBadRequest, 0, ""},
+	{"/accounts/0/tree?balance&amount=price", http.StatusBadRequest, 0, ""},
+	{"/accounts/666/tree", http.StatusNotFound, 0, ""},
+	{"/accounts/0/forest", http.StatusBadRequest, 0, ""},
+}
+
+func TestTreeHandler(t *testing.T) {
+	trns := testTransactions()
+	root := models.Account{ID: "0", Type: "ROOT"}
+	for _, act := range []*models.Account{trns[0].Splits[0].Account, trns[0].Splits[1].Account, trns[1].Splits[1].Account} {
+		act.Parent = &root
+		root.Children = append(root.Children, act)
+	}
+	h := TreeHandler{Data: &models.Book{Root: &root}}
+
+	for _, tt := range treeTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var node struct {
+			Account struct {
+				ID string `json:"id"`
+			} `json:"account"`
+			Balance  *models.Balance   `json:"balance"`
+			Children []json.RawMessage `json:"children"`
+		}
+		json.NewDecoder(res.Body).Decode(&node)
+		assert.Equal(t, tt.children, len(node.Children), "number of children does not match for %s", tt.path)
+		if tt.balance == "" {
+			assert.Nil(t, node.Balance, "unexpected balance for %s", tt.path)
+		} else if assert.NotNil(t, node.Balance, "missing balance for %s", tt.path) {
+			assert.Equal(t, tt.balance, node.Balance.Amount.String(), "balance does not match for %s", tt.path)
+		}
+	}
+}
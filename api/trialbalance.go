@@ -0,0 +1,45 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+type TrialBalanceHandler struct {
+	Data *models.Book
+}
+
+func (th *TrialBalanceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	if unsupportedOptions(params, "type", "norecursive", "amount") {
+		httpBadRequest(w, r)
+		return
+	}
+	var opts models.BalanceOptions
+	if err := currencyOption(params, th.Data, &opts); err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	date, err := dateOption(params, "date")
+	if err != nil {
+		httpBadRequest(w, r)
+		return
+	}
+	opts.To = date
+
+	resp, err := json.Marshal(th.Data.TrialBalance(opts))
+	if err != nil {
+		log.Printf("Unable to marshall trial balance to JSON: %s\n", err)
+		httpInternalServerError(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s", resp)
+}
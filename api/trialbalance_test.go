@@ -0,0 +1,57 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vinymeuh/gnc-api-d/models"
+)
+
+var trialBalanceTests = []struct {
+	path   string
+	status int
+	debit  string
+}{
+	{"/reports/trial-balance", http.StatusOK, "235.50"},
+	{"/reports/trial-balance?date=2019-01-31", http.StatusOK, "105.50"},
+	{"/reports/trial-balance?date=31/01/2019", http.StatusBadRequest, ""},
+	{"/reports/trial-balance?norecursive", http.StatusBadRequest, ""},
+}
+
+func TestTrialBalanceHandler(t *testing.T) {
+	trns := testTransactions()
+	root := models.Account{ID: "0", Type: "ROOT"}
+	for _, act := range []*models.Account{trns[0].Splits[0].Account, trns[0].Splits[1].Account, trns[1].Splits[1].Account} {
+		act.Parent = &root
+		root.Children = append(root.Children, act)
+	}
+	h := TrialBalanceHandler{Data: &models.Book{Root: &root, Transactions: trns}}
+
+	for _, tt := range trialBalanceTests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		res := w.Result()
+		assert.Equal(t, tt.status, res.StatusCode, "Status code is wrong for %s", tt.path)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var tb models.TrialBalance
+		json.NewDecoder(res.Body).Decode(&tb)
+		assert.Equal(t, 3, len(tb.Accounts), "number of accounts does not match for %s", tt.path)
+		assert.Equal(t, tt.debit, tb.TotalDebit.String(), "total debits does not match for %s", tt.path)
+		assert.Equal(t, tt.debit, tb.TotalCredit.String(), "total credits does not match for %s", tt.path)
+		assert.True(t, tb.Balanced, "trial balance is not balanced for %s", tt.path)
+	}
+}
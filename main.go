@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/vinymeuh/gnc-api-d/api"
 	"github.com/vinymeuh/gnc-api-d/models"
@@ -29,19 +30,50 @@ func getGnuCashFile() string {
 	return file
 }
 
+func getReloadInterval() time.Duration {
+	interval := os.Getenv("GNUCASH_RELOAD_INTERVAL")
+	if interval == "" {
+		return 10 * time.Second
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		log.Printf("variable GNUCASH_RELOAD_INTERVAL is not a valid duration: %s", err)
+		os.Exit(1)
+	}
+	return d
+}
+
+func getPathSeparator() string {
+	sep := os.Getenv("GNUCASH_PATH_SEPARATOR")
+	if sep == "" {
+		return ":"
+	}
+	return sep
+}
+
 func main() {
 	setupLog()
+	models.PathSeparator = getPathSeparator()
 
 	// load Gnucash data
 	gncfile := getGnuCashFile()
 	log.Printf("Loading GnuCash file '%s'", gncfile)
-	root, err := models.LoadFromFile(gncfile)
+	book, err := models.LoadFromFile(gncfile)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	store := models.NewStore(book)
+
+	// reload Gnucash data when modified
+	if interval := getReloadInterval(); interval > 0 {
+		log.Printf("Watching GnuCash file '%s' every %s", gncfile, interval)
+		w := models.NewWatcher(book, interval, store.Replace, store.SetError, store.ClearError)
+		go w.Run(nil)
+	}
+
 	// start HTTP server
-	r := api.NewRouter(root)
+	r := api.NewRouter(store)
 	addr := getListenAddress()
 	log.Printf("Starting HTTP server on %s", addr)
 	log.Fatal(http.ListenAndServe(addr, r))
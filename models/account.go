@@ -4,25 +4,81 @@
 package models
 
 import (
+	"encoding/json"
+	"sort"
+	"sync"
 	"time"
 )
 
 // Account is a node of the accounts hierarchy.
-// Each account has its own  list of transactions
+// Each account has its own list of splits, sorted by date
 type Account struct {
-	ID           string         `json:"id"`
-	Name         string         `json:"name"`
-	Type         string         `json:"type"`
-	Parent       *Account       `json:"-"`
-	Children     []*Account     `json:"-"`
-	Transactions []*Transaction `json:"-"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Path      string     `json:"path,omitempty"` // full path from the root, set when the book is indexed
+	Type      string     `json:"type"`
+	Commodity *Commodity `json:"commodity,omitempty"`
+	SCU       int64      `json:"-"` // smallest commodity unit, 100 for cents
+	Parent    *Account   `json:"-"`
+	Children  []*Account `json:"-"`
+	Splits    []*Split   `json:"-"`
+	Lots      []*Lot     `json:"-"`
+
+	Code        string `json:"code,omitempty"`
+	Description string `json:"description,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+	Placeholder bool   `json:"placeholder,omitempty"` // can not hold transactions, only sub-accounts
+	Hidden      bool   `json:"hidden,omitempty"`
+	TaxRelated  bool   `json:"tax_related,omitempty"`
+	Slots       Slots  `json:"slots,omitempty"`
+
+	ledgerOnce  sync.Once
+	ownLedger   *ledger
+	subtreeOnce sync.Once
+	subtree     *ledger
+}
+
+// Ref returns a short reference to the account
+func (a *Account) Ref() AccountRef {
+	return AccountRef{ID: a.ID, Name: a.Name}
 }
 
-// Transaction keeps data for a transaction
-type Transaction struct {
-	Num   string  `json:"-"`
-	Date  string  `json:"-"` // YYYY-MM-DD
-	Value float64 `json:"-"`
+// MarshalJSON writes the account with the ID of its parent
+func (a *Account) MarshalJSON() ([]byte, error) {
+	type account Account
+	var parent string
+	if a.Parent != nil {
+		parent = a.Parent.ID
+	}
+	return json.Marshal(struct {
+		*account
+		ParentID string `json:"parent_id,omitempty"`
+	}{account: (*account)(a), ParentID: parent})
+}
+
+// AccountNode is an account with its sub-accounts, used to return the hierarchy of accounts
+type AccountNode struct {
+	Account  *Account       `json:"account"`
+	Balance  *Balance       `json:"balance,omitempty"`
+	Children []*AccountNode `json:"children,omitempty"`
+}
+
+// Tree returns the hierarchy of accounts starting at a, down to depth levels of
+// sub-accounts (no limit if depth is negative). The balance of each account is
+// computed with opts when it is not nil.
+func (a *Account) Tree(depth int, opts *BalanceOptions) *AccountNode {
+	node := &AccountNode{Account: a}
+	if opts != nil {
+		b := a.Balance(*opts)
+		node.Balance = &b
+	}
+	if depth == 0 {
+		return node
+	}
+	for _, child := range a.Children {
+		node.Children = append(node.Children, child.Tree(depth-1, opts))
+	}
+	return node
 }
 
 // WalkAccountFunc is the type of the function called for each account visited by WalkBFS
@@ -56,6 +112,18 @@ func (a *Account) Descendants() []*Account {
 	return a.WalkBFS(func(act *Account) bool { return true })[1:]
 }
 
+// Ancestors returns the chain of parents of an account, from the root to its direct parent
+func (a *Account) Ancestors() []*Account {
+	acts := make([]*Account, 0)
+	for p := a.Parent; p != nil; p = p.Parent {
+		acts = append(acts, p)
+	}
+	for i, j := 0, len(acts)-1; i < j; i, j = i+1, j-1 {
+		acts[i], acts[j] = acts[j], acts[i]
+	}
+	return acts
+}
+
 // FindByID returns an accounts matching ID
 func (a *Account) FindByID(ID string) *Account {
 	acts := a.WalkBFS(func(act *Account) bool { return act.ID == ID })
@@ -75,43 +143,167 @@ func (a *Account) FindByType(atype string) []*Account {
 	return a.WalkBFS(func(act *Account) bool { return act.Type == atype })
 }
 
-// BalanceOptions is the type used as input parameters for the Balance function
+// BalanceOptions is the type used as input parameters for the Balance function.
+// By default splits are summed by value, in the currency of their transaction.
+// With Quantity, they are summed by quantity, in the commodity of the account (units, shares...).
+// When Currency is set, amounts are converted using the most recent price at or
+// before To found in Prices.
 type BalanceOptions struct {
 	From      string
 	To        string
 	Type      string
 	Recursive bool
+	Quantity  bool
+	Currency  *Commodity
+	Prices    *PriceDB
 }
 
-// Balance is the type used to return result for the Balance function
+// Balance is the type used to return result for the Balance function.
+// Value is kept for backward compatibility, Amount is the exact result.
+// Unpriced lists the commodities that could not be converted to Currency.
+// Mixed lists the commodities added together when no Currency is set.
 type Balance struct {
-	Date  string
-	Value float64
+	Date     string
+	Value    float64
+	Amount   Amount
+	Currency string   `json:",omitempty"`
+	Unpriced []string `json:",omitempty"`
+	Mixed    []string `json:",omitempty"`
+
+	commodities []string // commodities of the non zero amounts added without conversion
 }
 
-// Balance returns the amount of the account
+// Balance returns the amount of the account.
+// Splits are kept in ledgers sorted by date with cumulative sums, so unless
+// they are filtered by Type, the amount between two dates is found with a
+// binary search, on the ledger of the whole sub-tree when no conversion is needed.
+// With Currency, the quantities held in the commodity of each account are converted at opts.To.
+// Without Currency, amounts in different commodities are added as they are and listed in Mixed.
 func (a *Account) Balance(opts BalanceOptions) Balance {
 
 	if opts.To == "" {
 		opts.To = time.Now().Format("2006-01-02")
 	}
+	if opts.Currency != nil {
+		// the balance held in the commodity of each account is converted, not the values of the past transactions
+		opts.Quantity = true
+	}
 
-	var b float64
-	// transactions directly attached to the account
-	for _, t := range a.Transactions {
-		if opts.Type != "" && t.Num != opts.Type {
-			continue
-		}
-		if t.Date >= opts.From && t.Date <= opts.To {
-			b = b + t.Value
+	b := Amount{SCU: a.SCU}
+	balance := Balance{Date: opts.To}
+	if opts.Currency != nil {
+		balance.Currency = opts.Currency.String()
+	}
+
+	if opts.Recursive && opts.Currency == nil && opts.Type == "" && !a.subtreeLedger().mixedIn(opts.Quantity) {
+		b = b.Add(a.subtreeLedger().sum(opts.From, opts.To, opts.Quantity))
+		if c := a.subtreeLedger().commodityOf(opts.Quantity); c != nil && !b.IsZero() {
+			balance.commodities = []string{c.String()}
 		}
+		balance.Value = b.Float64()
+		balance.Amount = b
+		return balance
 	}
+
+	// transactions directly attached to the account
+	own, unpriced, commodities := opts.total(a.amounts(opts), opts.To)
+	b = b.Add(own)
+	balance.Unpriced = unpriced
+	balance.commodities = commodities
 	// transactions on sub-accounts
 	if opts.Recursive {
 		for _, sa := range a.Children {
-			b = b + sa.Balance(opts).Value
+			sb := sa.Balance(opts)
+			b = b.Add(sb.Amount)
+			for _, c := range sb.Unpriced {
+				balance.Unpriced = appendOnce(balance.Unpriced, c)
+			}
+			for _, c := range sb.commodities {
+				balance.commodities = appendOnce(balance.commodities, c)
+			}
+		}
+	}
+	if len(balance.commodities) > 1 {
+		sort.Strings(balance.commodities)
+		balance.Mixed = balance.commodities
+	}
+
+	if opts.Currency != nil && b.SCU < opts.Currency.Fraction {
+		b = b.WithSCU(opts.Currency.Fraction)
+	}
+	balance.Value = b.Float64()
+	balance.Amount = b
+	return balance
+}
+
+// total returns the sum of amounts expressed in several commodities,
+// converted to opts.Currency at date when set, the list of commodities without price
+// and, without opts.Currency, the list of commodities added as they are.
+func (opts BalanceOptions) total(amounts map[*Commodity]Amount, date string) (Amount, []string, []string) {
+	var total Amount
+	var unpriced, commodities []string
+	for c, amount := range amounts {
+		if opts.Currency == nil && c != nil && !amount.IsZero() {
+			commodities = appendOnce(commodities, c.String())
+		}
+		if opts.Currency != nil && c != nil && c != opts.Currency && !amount.IsZero() {
+			converted, ok := opts.Prices.Convert(amount, c, opts.Currency, date)
+			if !ok {
+				unpriced = appendOnce(unpriced, c.String())
+			}
+			amount = converted
+		}
+		total = total.Add(amount)
+	}
+	sort.Strings(unpriced)
+	sort.Strings(commodities)
+	return total, unpriced, commodities
+}
+
+// amounts returns the sums of the splits of the account matching opts, by commodity.
+// Quantities are in the commodity of the account, values in the currency of their transaction.
+func (a *Account) amounts(opts BalanceOptions) map[*Commodity]Amount {
+	if opts.Type == "" {
+		l := a.ledger()
+		if opts.Quantity {
+			return map[*Commodity]Amount{a.Commodity: l.sum(opts.From, opts.To, true)}
+		}
+		if !l.mixed {
+			c := l.currency
+			if c == nil {
+				c = a.Commodity
+			}
+			return map[*Commodity]Amount{c: l.sum(opts.From, opts.To, false)}
+		}
+	}
+
+	amounts := make(map[*Commodity]Amount)
+	for _, s := range a.Splits {
+		t := s.Transaction
+		if opts.Type != "" && t.Num != opts.Type {
+			continue
+		}
+		if t.DatePosted >= opts.From && t.DatePosted <= opts.To {
+			c := a.commodityOf(s, opts)
+			amounts[c] = amounts[c].Add(s.Amount(opts))
 		}
 	}
+	return amounts
+}
 
-	return Balance{Date: opts.To, Value: b}
+// commodityOf returns the commodity in which the amount of the split s of the account is expressed
+func (a *Account) commodityOf(s *Split, opts BalanceOptions) *Commodity {
+	if !opts.Quantity && s.Transaction.Currency != nil {
+		return s.Transaction.Currency
+	}
+	return a.Commodity
+}
+
+func appendOnce(l []string, s string) []string {
+	for _, e := range l {
+		if e == s {
+			return l
+		}
+	}
+	return append(l, s)
 }
@@ -4,15 +4,16 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 var accountBalanceTests = []struct {
-	options		BalanceOptions
-	expected 	float64
-	errmsg  	string
+	options  BalanceOptions
+	expected float64
+	errmsg   string
 }{
 	{BalanceOptions{}, 1490.0, "Non recursive current Balance is incorrect"},
 	{BalanceOptions{Recursive: true}, 1374.5, "Recursive current Balance is incorrect"},
@@ -26,28 +27,28 @@ func TestAccount(t *testing.T) {
 		ID:   "0",
 		Name: "Dummy Account",
 		Type: "ROOT",
-		Transactions: []*Transaction{
-			{Date: "2019-01-01", Value: 1000.0},
-			{Date: "2019-01-02", Value: -9.5, Num: "X"},
-			{Date: "2019-01-03", Value: -0.5, Num: "X"},
-			{Date: "2019-02-01", Value: 1000.0},
-			{Date: "2019-02-03", Value: -500.0},
+		Splits: []*Split{
+			{Value: NewAmount(100000, 100), Transaction: &Transaction{DatePosted: "2019-01-01"}},
+			{Value: NewAmount(-950, 100), Transaction: &Transaction{DatePosted: "2019-01-02", Num: "X"}},
+			{Value: NewAmount(-50, 100), Transaction: &Transaction{DatePosted: "2019-01-03", Num: "X"}},
+			{Value: NewAmount(100000, 100), Transaction: &Transaction{DatePosted: "2019-02-01"}},
+			{Value: NewAmount(-50000, 100), Transaction: &Transaction{DatePosted: "2019-02-03"}},
 		},
 		Children: []*Account{
 			{
 				ID:   "1",
 				Name: "Account 1",
 				Type: "BANK",
-				Transactions: []*Transaction{
-					{Date: "2019-01-05", Value: -100.0},
+				Splits: []*Split{
+					{Value: NewAmount(-10000, 100), Transaction: &Transaction{DatePosted: "2019-01-05"}},
 				},
 			},
 			{
 				ID:   "2",
 				Name: "Account 2",
 				Type: "BANK",
-				Transactions: []*Transaction{
-					{Date: "2019-02-20", Value: -15.5},
+				Splits: []*Split{
+					{Value: NewAmount(-1550, 100), Transaction: &Transaction{DatePosted: "2019-02-20"}},
 				},
 			},
 		},
@@ -71,3 +72,105 @@ func TestAccount(t *testing.T) {
 		assert.Equal(t, tt.expected, root.Balance(tt.options).Value, tt.errmsg)
 	}
 }
+
+func TestAccountBalanceWithCurrency(t *testing.T) {
+	acts := Account{
+		ID:        "0",
+		Type:      "ASSET",
+		Commodity: eur,
+		Splits: []*Split{
+			{Value: NewAmount(10000, 100), Quantity: NewAmount(10000, 100), Transaction: &Transaction{DatePosted: "2019-01-01"}},
+		},
+		Children: []*Account{
+			{
+				ID:        "1",
+				Type:      "BANK",
+				Commodity: usd,
+				Splits: []*Split{
+					{Value: NewAmount(12500, 100), Quantity: NewAmount(12500, 100), Transaction: &Transaction{DatePosted: "2019-01-15"}},
+				},
+			},
+			{
+				ID:        "2",
+				Type:      "STOCK",
+				Commodity: aapl,
+				Splits: []*Split{
+					{Value: NewAmount(5, 1), Quantity: NewAmount(5, 1), Transaction: &Transaction{DatePosted: "2019-02-01"}},
+				},
+			},
+		},
+	}
+	db := testPriceDB()
+
+	b := acts.Balance(BalanceOptions{To: "2019-01-31", Recursive: true, Currency: eur, Prices: db})
+	assert.Equal(t, "200.00", b.Amount.String(), "Balance converted to EUR is incorrect")
+	assert.Equal(t, "CURRENCY:EUR", b.Currency, "Currency of converted balance is incorrect")
+	assert.Empty(t, b.Unpriced, "Balance converted to EUR should not have unpriced commodities")
+
+	b = acts.Balance(BalanceOptions{To: "2019-03-01", Recursive: true, Currency: usd, Prices: db})
+	assert.Equal(t, "1088.00", b.Amount.String(), "Balance converted to USD is incorrect")
+
+	b = acts.Balance(BalanceOptions{To: "2019-01-31", Recursive: true, Currency: usd, Prices: NewPriceDB(nil)})
+	assert.Equal(t, "125.00", b.Amount.String(), "Balance with unpriced commodities is incorrect")
+	assert.Equal(t, []string{"CURRENCY:EUR"}, b.Unpriced, "Unpriced commodities are incorrect")
+
+	b = acts.Balance(BalanceOptions{To: "2019-01-31", Recursive: true})
+	assert.Equal(t, "225.00", b.Amount.String(), "Balance without currency is incorrect")
+	assert.Equal(t, []string{"CURRENCY:EUR", "CURRENCY:USD"}, b.Mixed, "Mixed commodities are incorrect")
+
+	b = acts.Balance(BalanceOptions{To: "2019-01-31", Recursive: false})
+	assert.Empty(t, b.Mixed, "Balance of a single account should not have mixed commodities")
+}
+
+func TestAccountTree(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expenses := book.AccountByPath("Expenses")
+
+	tree := expenses.Tree(-1, nil)
+	assert.Equal(t, expenses, tree.Account, "Problem with account of tree node")
+	assert.Nil(t, tree.Balance, "Problem with tree without balance")
+	if assert.Equal(t, 3, len(tree.Children), "Problem with children of tree node") {
+		assert.Equal(t, "Auto", tree.Children[1].Account.Name, "Problem with child of tree node")
+		assert.Equal(t, 1, len(tree.Children[1].Children), "Problem with grandchildren of tree node")
+	}
+
+	tree = expenses.Tree(1, nil)
+	if assert.Equal(t, 3, len(tree.Children), "Problem with children of tree node") {
+		assert.Nil(t, tree.Children[1].Children, "Problem with tree limited in depth")
+	}
+	assert.Nil(t, expenses.Tree(0, nil).Children, "Problem with tree of depth 0")
+
+	tree = expenses.Tree(-1, &BalanceOptions{To: "2019-12-31", Recursive: true})
+	if assert.NotNil(t, tree.Balance, "Problem with tree with balance") {
+		assert.Equal(t, "415.20", tree.Balance.Amount.String(), "Problem with balance of tree node")
+		assert.Equal(t, "300.00", tree.Children[2].Balance.Amount.String(), "Problem with balance of tree child node")
+	}
+
+	b, err := json.Marshal(expenses.Tree(1, nil))
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(b), `"name":"Auto","path":"Expenses:Auto","type":"EXPENSE"`, "Problem with JSON of tree")
+		assert.Contains(t, string(b), `"parent_id":"`+expenses.ID+`"`, "Problem with parent in JSON of tree")
+	}
+	b, err = json.Marshal(book.Root)
+	if assert.NoError(t, err) {
+		assert.NotContains(t, string(b), "parent_id", "Problem with JSON of root account")
+	}
+}
+
+func TestAccountAncestors(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ancestors := book.AccountByPath("Expenses:Auto:Fuel").Ancestors()
+	if assert.Equal(t, 3, len(ancestors), "Problem with the number of ancestors") {
+		assert.Equal(t, book.Root, ancestors[0], "Problem with first ancestor")
+		assert.Equal(t, "Expenses", ancestors[1].Name, "Problem with ancestors order")
+		assert.Equal(t, "Auto", ancestors[2].Name, "Problem with last ancestor")
+	}
+	assert.Equal(t, 0, len(book.Root.Ancestors()), "Problem with ancestors of root account")
+}
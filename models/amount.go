@@ -0,0 +1,340 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact rational number as stored by GnuCash ("12345/100").
+// SCU is the smallest commodity unit used to render the amount, 0 if unknown.
+type Amount struct {
+	Num   int64
+	Denom int64
+	SCU   int64
+}
+
+// NewAmount returns the amount num/denom
+func NewAmount(num, denom int64) Amount {
+	return Amount{Num: num, Denom: denom, SCU: denom}
+}
+
+// ParseAmount reads an amount written either as a GnuCash fraction ("12345/100")
+// or as a decimal number ("-123.45")
+func ParseAmount(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Amount{}, errors.New("empty amount")
+	}
+
+	if i := strings.Index(s, "/"); i >= 0 {
+		n, err := strconv.ParseInt(s[:i], 10, 64)
+		if err != nil {
+			return Amount{}, err
+		}
+		d, err := strconv.ParseInt(s[i+1:], 10, 64)
+		if err != nil {
+			return Amount{}, err
+		}
+		if d == 0 {
+			return Amount{}, errors.New("amount with a zero denominator")
+		}
+		if d < 0 {
+			n, d = -n, -d
+		}
+		return NewAmount(n, d), nil
+	}
+
+	d := int64(1)
+	digits := s
+	if i := strings.Index(s, "."); i >= 0 {
+		for range s[i+1:] {
+			d = d * 10
+		}
+		digits = s[:i] + s[i+1:]
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Amount{}, err
+	}
+	return NewAmount(n, d), nil
+}
+
+func (a Amount) denom() int64 {
+	if a.Denom == 0 {
+		return 1
+	}
+	return a.Denom
+}
+
+// Add returns the sum a+b
+func (a Amount) Add(b Amount) Amount {
+	scu := a.SCU
+	if b.SCU > scu {
+		scu = b.SCU
+	}
+	ad, bd := a.denom(), b.denom()
+	if ad == bd {
+		if n, ok := add64(a.Num, b.Num); ok {
+			return Amount{Num: n, Denom: ad, SCU: scu}
+		}
+		return newAmountFromRat(new(big.Rat).Add(a.rat(), b.rat()), scu)
+	}
+	m, ok := mul64(ad/gcd(ad, bd), bd)
+	if ok {
+		an, aok := mul64(a.Num, m/ad)
+		bn, bok := mul64(b.Num, m/bd)
+		if n, nok := add64(an, bn); aok && bok && nok {
+			return Amount{Num: n, Denom: m, SCU: scu}
+		}
+	}
+	return newAmountFromRat(new(big.Rat).Add(a.rat(), b.rat()), scu)
+}
+
+// Sub returns the difference a-b
+func (a Amount) Sub(b Amount) Amount {
+	return a.Add(b.Neg())
+}
+
+// Neg returns -a
+func (a Amount) Neg() Amount {
+	return Amount{Num: -a.Num, Denom: a.Denom, SCU: a.SCU}
+}
+
+// Mul returns the product a*b
+func (a Amount) Mul(b Amount) Amount {
+	scu := a.SCU
+	if b.SCU > scu {
+		scu = b.SCU
+	}
+	an, ad := a.Num, a.denom()
+	bn, bd := b.Num, b.denom()
+	// cross reduce to keep numbers small
+	if g := gcd(abs(an), bd); g > 1 {
+		an, bd = an/g, bd/g
+	}
+	if g := gcd(abs(bn), ad); g > 1 {
+		bn, ad = bn/g, ad/g
+	}
+	n, nok := mul64(an, bn)
+	d, dok := mul64(ad, bd)
+	if nok && dok {
+		return Amount{Num: n, Denom: d, SCU: scu}
+	}
+	return newAmountFromRat(new(big.Rat).Mul(a.rat(), b.rat()), scu)
+}
+
+// Inv returns 1/a, or 0 if a is equal to 0
+func (a Amount) Inv() Amount {
+	if a.Num == 0 {
+		return Amount{SCU: a.SCU}
+	}
+	if a.Num < 0 {
+		return Amount{Num: -a.denom(), Denom: -a.Num, SCU: a.SCU}
+	}
+	return Amount{Num: a.denom(), Denom: a.Num, SCU: a.SCU}
+}
+
+// Round returns a rounded half away from zero to the nearest multiple of 1/denom
+func (a Amount) Round(denom int64) Amount {
+	if denom <= 0 || a.denom() == denom {
+		return a
+	}
+	n, ok := mul64(a.Num, denom)
+	if !ok {
+		return Amount{Num: roundRat(a.rat(), denom), Denom: denom, SCU: denom}
+	}
+	q, r := n/a.denom(), n%a.denom()
+	if 2*abs(r) >= a.denom() {
+		if n < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Amount{Num: q, Denom: denom, SCU: denom}
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of a
+func (a Amount) Sign() int {
+	switch {
+	case a.Num < 0:
+		return -1
+	case a.Num > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero reports whether a is equal to 0
+func (a Amount) IsZero() bool {
+	return a.Num == 0
+}
+
+// Cmp compares a and b and returns -1, 0 or +1
+func (a Amount) Cmp(b Amount) int {
+	return a.Sub(b).Sign()
+}
+
+// WithSCU returns a with the smallest commodity unit used to render it set to scu
+func (a Amount) WithSCU(scu int64) Amount {
+	a.SCU = scu
+	return a
+}
+
+// Float64 returns the nearest floating point value for a
+func (a Amount) Float64() float64 {
+	return float64(a.Num) / float64(a.denom())
+}
+
+// String returns the exact decimal representation of a, with at least as many
+// decimals as required by its SCU. An amount that has no finite decimal
+// representation is written as a fraction.
+func (a Amount) String() string {
+	n, d := a.Num, a.denom()
+	if g := gcd(abs(n), d); g > 1 {
+		n, d = n/g, d/g
+	}
+
+	// scale to a power of ten
+	p := int64(1)
+	decimals := 0
+	for p%d != 0 {
+		if decimals == 18 {
+			return strconv.FormatInt(a.Num, 10) + "/" + strconv.FormatInt(a.denom(), 10)
+		}
+		p = p * 10
+		decimals++
+	}
+	for p < a.SCU && decimals < 18 {
+		p = p * 10
+		decimals++
+	}
+	n, ok := mul64(n, p/d)
+	if !ok {
+		return a.rat().FloatString(decimals)
+	}
+
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	s := strconv.FormatInt(n, 10)
+	if decimals == 0 {
+		return sign + s
+	}
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+	return sign + s[:len(s)-decimals] + "." + s[len(s)-decimals:]
+}
+
+// MarshalJSON writes the amount as an exact decimal string
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON reads an amount written as a string by MarshalJSON
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	v, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func lcm(a, b int64) int64 {
+	return a / gcd(a, b) * b
+}
+
+// rat returns a as a big.Rat, used when the result of an operation does not fit in int64
+func (a Amount) rat() *big.Rat {
+	return big.NewRat(a.Num, a.denom())
+}
+
+// newAmountFromRat returns r as an amount. When r can not be written with int64, it is rounded to
+// the largest power of ten denominator that keeps its numerator in int64.
+func newAmountFromRat(r *big.Rat, scu int64) Amount {
+	if r.Num().IsInt64() && r.Denom().IsInt64() {
+		return Amount{Num: r.Num().Int64(), Denom: r.Denom().Int64(), SCU: scu}
+	}
+	for d := int64(1000000000000000000); d > 1; d /= 10 {
+		if n, ok := roundRatFits(r, d); ok {
+			return Amount{Num: n, Denom: d, SCU: scu}
+		}
+	}
+	return Amount{Num: roundRat(r, 1), Denom: 1, SCU: scu}
+}
+
+// roundRat returns r rounded half away from zero to the nearest multiple of 1/denom, as a numerator
+// over denom, saturated to the limits of int64
+func roundRat(r *big.Rat, denom int64) int64 {
+	n, ok := roundRatFits(r, denom)
+	if !ok {
+		if r.Sign() < 0 {
+			return math.MinInt64 + 1
+		}
+		return math.MaxInt64
+	}
+	return n
+}
+
+// roundRatFits returns r rounded half away from zero to the nearest multiple of 1/denom, as a numerator
+// over denom, and false if this numerator does not fit in int64
+func roundRatFits(r *big.Rat, denom int64) (int64, bool) {
+	n := new(big.Int).Mul(r.Num(), big.NewInt(denom))
+	q, m := new(big.Int).QuoRem(n, r.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(n.Sign())))
+	}
+	if !q.IsInt64() {
+		return 0, false
+	}
+	return q.Int64(), true
+}
+
+// add64 returns a+b and false if the sum overflows
+func add64(a, b int64) (int64, bool) {
+	c := a + b
+	if (b > 0 && c < a) || (b < 0 && c > a) {
+		return 0, false
+	}
+	return c, true
+}
+
+// mul64 returns a*b and false if the product overflows
+func mul64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return c, true
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var amountParseTests = []struct {
+	input    string
+	expected string
+}{
+	{"12345/100", "123.45"},
+	{"-100000/100", "-1000.00"},
+	{"5/100", "0.05"},
+	{"-5/100", "-0.05"},
+	{"0/1", "0"},
+	{"3/1", "3"},
+	{"1/3", "1/3"},
+	{"1/8", "0.125"},
+	{"-123.45", "-123.45"},
+	{"42", "42"},
+}
+
+func TestParseAmount(t *testing.T) {
+	for _, tt := range amountParseTests {
+		a, err := ParseAmount(tt.input)
+		if assert.NoError(t, err, tt.input) {
+			assert.Equal(t, tt.expected, a.String(), "Problem while parsing amount %s", tt.input)
+		}
+	}
+
+	for _, input := range []string{"", "abc", "1/0", "1/x"} {
+		_, err := ParseAmount(input)
+		assert.Error(t, err, "Parsing invalid amount '%s' should fail", input)
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	// 0.1 added ten thousand times is exactly 1000
+	sum := Amount{}
+	for i := 0; i < 10000; i++ {
+		sum = sum.Add(NewAmount(10, 100))
+	}
+	assert.Equal(t, "1000.00", sum.String(), "Problem with sum of amounts")
+	assert.Equal(t, 1000.0, sum.Float64(), "Problem with float value of amount")
+
+	a := NewAmount(1, 3).Add(NewAmount(1, 6))
+	assert.Equal(t, "0.5", a.String(), "Problem with sum of amounts with different denominators")
+	assert.Equal(t, "-0.50", NewAmount(1, 2).Sub(NewAmount(100, 100)).String(), "Problem with difference of amounts")
+	assert.Equal(t, 1, NewAmount(1, 2).Cmp(NewAmount(49, 100)), "Problem with comparison of amounts")
+	assert.Equal(t, 0, NewAmount(1, 2).Cmp(NewAmount(50, 100)), "Problem with comparison of amounts")
+	assert.True(t, NewAmount(0, 100).IsZero(), "Problem with zero amount")
+	assert.Equal(t, "12.5000", NewAmount(125, 10).WithSCU(10000).String(), "Problem with amount rendered with SCU")
+}
+
+func TestAmountJSON(t *testing.T) {
+	b, err := json.Marshal(NewAmount(-1550, 100))
+	if assert.NoError(t, err) {
+		assert.Equal(t, `"-15.50"`, string(b), "Problem while marshalling amount")
+	}
+
+	var a Amount
+	if assert.NoError(t, json.Unmarshal(b, &a)) {
+		assert.Equal(t, 0, a.Cmp(NewAmount(-155, 10)), "Problem while unmarshalling amount")
+	}
+}
+
+func TestAmountConversion(t *testing.T) {
+	price := NewAmount(11234, 10000)
+	a := NewAmount(10000, 100).Mul(price)
+	assert.Equal(t, "112.3400", a.String(), "Problem with product of amounts")
+	assert.Equal(t, "112.34", a.Round(100).String(), "Problem with rounded product of amounts")
+
+	b := NewAmount(11234, 100).Mul(price.Inv()).Round(100)
+	assert.Equal(t, "100.00", b.String(), "Problem with inverse of amount")
+
+	assert.Equal(t, "0.67", NewAmount(2, 3).Round(100).String(), "Problem with rounding of amount")
+	assert.Equal(t, "-0.67", NewAmount(-2, 3).Round(100).String(), "Problem with rounding of negative amount")
+	assert.Equal(t, "0.13", NewAmount(125, 1000).Round(100).String(), "Problem with rounding half away from zero")
+}
+
+func TestAmountOverflow(t *testing.T) {
+	// products of high precision prices do not fit in int64
+	a := NewAmount(1234, 100).Mul(NewAmount(8571428571428571, 10000000000000000))
+	assert.Equal(t, "10.58", a.Round(100).String(), "Problem with conversion at a high precision price")
+
+	rate := NewAmount(1234567891, 1000000000)
+	a = NewAmount(9876543210, 100).Mul(rate)
+	assert.Equal(t, "121932631.2114007011", a.String(), "Problem with exact product of large amounts")
+	assert.Equal(t, "121932631.21", a.Round(100).String(), "Problem with conversion of a large amount")
+	assert.Equal(t, "80000000.66", NewAmount(9876543210, 100).Mul(rate.Inv()).Round(100).String(), "Problem with conversion at the inverse rate")
+
+	// sums of amounts with large denominators
+	b := NewAmount(1, 3000000000).Add(NewAmount(1, 7000000000))
+	assert.Equal(t, 1, b.Sign(), "Problem with sign of a sum with large denominators")
+	assert.InDelta(t, 1.0/3000000000+1.0/7000000000, b.Float64(), 1e-18, "Problem with sum of amounts with large denominators")
+	c := NewAmount(math.MaxInt64-1, 100).Add(NewAmount(500, 100))
+	assert.Equal(t, 1, c.Sign(), "Problem with sign of a sum overflowing int64")
+	assert.Equal(t, -1, NewAmount(1, 3000000000).Cmp(NewAmount(1, 2999999999)), "Problem with comparison of amounts with large denominators")
+}
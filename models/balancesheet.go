@@ -0,0 +1,96 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// BalanceSheet is the state of assets, liabilities and equity at a date, valued with the prices at this date.
+// Liabilities and equity are shown as positive numbers like in GnuCash.
+// RetainedEarnings is the net income of all income and expense accounts up to the date,
+// which is part of equity until it is closed into an equity account.
+// UnrealizedGains is the change of value of the accounts due to prices since their transactions.
+// Difference is the imbalance of the unbalanced transactions and should be zero, so that
+// Assets = Liabilities + Equity + RetainedEarnings + UnrealizedGains + Difference.
+type BalanceSheet struct {
+	Date             string        `json:"date"`
+	Currency         string        `json:"currency,omitempty"`
+	Assets           ReportSection `json:"assets"`
+	Liabilities      ReportSection `json:"liabilities"`
+	Equity           ReportSection `json:"equity"`
+	RetainedEarnings Amount        `json:"retained_earnings"`
+	UnrealizedGains  Amount        `json:"unrealized_gains"`
+	Difference       Amount        `json:"difference"`
+	Balanced         bool          `json:"balanced"`
+	Unpriced         []string      `json:"unpriced,omitempty"`
+}
+
+// BalanceSheet returns the balance sheet of the book at opts.To, in opts.Currency or by default
+// in the commodity of the root account, so that stocks and foreign currencies are at their market value.
+func (b *Book) BalanceSheet(opts BalanceOptions) BalanceSheet {
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+	if opts.Currency == nil && b.Root != nil {
+		opts.Currency = b.Root.Commodity
+	}
+	if opts.Prices == nil {
+		opts.Prices = b.Prices
+	}
+	opts = BalanceOptions{To: opts.To, Currency: opts.Currency, Prices: opts.Prices}
+
+	bs := BalanceSheet{Date: opts.To}
+	var unpriced [5][]string
+	bs.Assets, unpriced[0] = newReportSection(b.Root, AssetTypes, opts, false)
+	bs.Liabilities, unpriced[1] = newReportSection(b.Root, LiabilityTypes, opts, true)
+	bs.Equity, unpriced[2] = newReportSection(b.Root, EquityTypes, opts, true)
+	var earnings ReportSection
+	earnings, unpriced[3] = newReportSection(b.Root, append(append([]string{}, IncomeTypes...), ExpenseTypes...), opts, true)
+	bs.RetainedEarnings = earnings.Total
+
+	// the values of the splits of a balanced transaction sum to zero, so once converted at the date of the
+	// transaction all accounts sum to the imbalance of the transactions. The rest of the gap between the
+	// converted accounts comes from the prices at opts.To.
+	bs.Difference, unpriced[4] = b.imbalance(opts)
+	bs.UnrealizedGains = bs.Assets.Total.Sub(bs.Liabilities.Total).Sub(bs.Equity.Total).Sub(bs.RetainedEarnings).Sub(bs.Difference)
+	bs.Balanced = bs.Difference.IsZero()
+	for _, l := range unpriced {
+		for _, c := range l {
+			bs.Unpriced = appendOnce(bs.Unpriced, c)
+		}
+	}
+	sort.Strings(bs.Unpriced)
+	if opts.Currency != nil {
+		bs.Currency = opts.Currency.String()
+	}
+	return bs
+}
+
+// imbalance returns the sum of the imbalances of the unbalanced transactions up to opts.To, converted to
+// opts.Currency at their date, and the list of commodities without price.
+func (b *Book) imbalance(opts BalanceOptions) (Amount, []string) {
+	var total Amount
+	var unpriced []string
+	for _, t := range b.Unbalanced {
+		if t.DatePosted > opts.To {
+			continue
+		}
+		imbalance := t.Imbalance()
+		if opts.Currency != nil && t.Currency != nil && t.Currency != opts.Currency {
+			converted, ok := opts.Prices.Convert(imbalance, t.Currency, opts.Currency, t.DatePosted)
+			if !ok {
+				unpriced = appendOnce(unpriced, t.Currency.String())
+			}
+			imbalance = converted
+		}
+		total = total.Add(imbalance)
+	}
+	if opts.Currency != nil && opts.Currency.Fraction > 0 {
+		total = total.Round(opts.Currency.Fraction)
+	}
+	sort.Strings(unpriced)
+	return total, unpriced
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceSheet(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs := book.BalanceSheet(BalanceOptions{To: "2019-12-31"})
+	assert.Equal(t, "2019-12-31", bs.Date, "Problem with balance sheet date")
+	assert.Equal(t, "CURRENCY:EUR", bs.Currency, "Problem with default currency of balance sheet")
+	assert.Empty(t, bs.Unpriced, "Problem with unpriced commodities")
+	assert.Equal(t, "4664.45", bs.Assets.Total.String(), "Problem with total of assets")
+	assert.Equal(t, "0.00", bs.Liabilities.Total.String(), "Problem with total of liabilities")
+	assert.Equal(t, "1000.00", bs.Equity.Total.String(), "Problem with total of equity")
+	assert.Equal(t, "3620.20", bs.RetainedEarnings.String(), "Problem with retained earnings")
+	assert.Equal(t, "44.25", bs.UnrealizedGains.String(), "Problem with unrealized gains")
+	assert.True(t, bs.Balanced, "Balance sheet should be balanced, difference is %s", bs.Difference)
+
+	if assert.Equal(t, 1, len(bs.Assets.Accounts), "Problem with the number of top level assets") {
+		assets := bs.Assets.Accounts[0]
+		assert.Equal(t, "Assets", assets.Account.Name, "Problem with top level asset")
+		assert.True(t, assets.Amount.IsZero(), "Problem with amount of a placeholder account")
+		if assert.Equal(t, 3, len(assets.Children), "Problem with sub-accounts of assets") {
+			assert.Equal(t, "3584.80", assets.Children[0].Total.String(), "Problem with total of %s", assets.Children[0].Account.Name)
+			assert.Equal(t, "451.33", assets.Children[2].Total.String(), "Problem with total of %s", assets.Children[2].Account.Name)
+		}
+	}
+
+	bs = book.BalanceSheet(BalanceOptions{To: "2019-01-31"})
+	assert.Equal(t, "115.20", bs.Liabilities.Total.String(), "Problem with total of liabilities")
+	assert.True(t, bs.Balanced, "Balance sheet should be balanced, difference is %s", bs.Difference)
+	assert.True(t, bs.UnrealizedGains.IsZero(), "Problem with unrealized gains before any price change")
+
+	usd := book.Commodities.Find(CurrencySpace, "USD")
+	bs = book.BalanceSheet(BalanceOptions{To: "2019-12-31", Currency: usd})
+	assert.Equal(t, "CURRENCY:USD", bs.Currency, "Problem with balance sheet currency")
+	assert.Empty(t, bs.Unpriced, "Problem with unpriced commodities")
+	assert.Equal(t, "5270.82", bs.Assets.Total.String(), "Problem with total of assets")
+	assert.Equal(t, "50.00", bs.UnrealizedGains.String(), "Problem with unrealized gains")
+	assert.True(t, bs.Balanced, "Balance sheet should be balanced, difference is %s", bs.Difference)
+	assert.Equal(t, bs.Assets.Total, bs.Liabilities.Total.Add(bs.Equity.Total).Add(bs.RetainedEarnings).Add(bs.UnrealizedGains), "Problem with accounting equation")
+	if assets := bs.Assets.Accounts[0]; assert.Equal(t, 3, len(assets.Children), "Problem with sub-accounts of assets") {
+		assert.Equal(t, "710.00", assets.Children[1].Total.String(), "Problem with total of %s", assets.Children[1].Account.Name)
+		assert.Equal(t, "510.00", assets.Children[2].Total.String(), "Problem with total of %s", assets.Children[2].Account.Name)
+	}
+
+	empty := Book{}
+	bs = empty.BalanceSheet(BalanceOptions{})
+	assert.True(t, bs.Balanced, "Balance sheet of an empty book should be balanced")
+	assert.Equal(t, 0, len(bs.Assets.Accounts), "Problem with assets of an empty book")
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// Book is the content of a GnuCash file
+type Book struct {
+	Root         *Account
+	Commodities  Commodities
+	Prices       *PriceDB
+	Slots        Slots        // options and features of the book
+	Transactions Transactions // sorted by date
+	Unbalanced   Transactions // transactions whose splits do not sum to zero, found by Load
+	Scheduled    []*ScheduledTransaction
+	Budgets      Budgets
+	File         FileInfo // empty if not loaded from a file
+	LoadedAt     time.Time
+
+	indexOnce sync.Once
+	byID      map[string]*Account
+	byName    map[string][]*Account
+	byType    map[string][]*Account
+	byPath    map[string]*Account
+}
+
+// PathSeparator is the separator used in the full path of accounts ("Expenses:Auto:Fuel").
+// It must be set before loading books.
+var PathSeparator = ":"
+
+// FileInfo describes the file a book has been loaded from
+type FileInfo struct {
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"modified"`
+	Size    int64     `json:"size"`
+}
+
+// Changed reports whether the file described by fi has been modified since other
+func (fi FileInfo) Changed(other FileInfo) bool {
+	return fi.Size != other.Size || !fi.ModTime.Equal(other.ModTime)
+}
+
+// index builds the indexes of the book on first use.
+// Accounts lists are kept in the Breadth-first order used by WalkBFS.
+func (b *Book) index() {
+	b.indexOnce.Do(func() {
+		if b.byID == nil {
+			b.byID = make(map[string]*Account)
+		}
+		b.byName = make(map[string][]*Account)
+		b.byType = make(map[string][]*Account)
+		b.byPath = make(map[string]*Account)
+		if b.Root == nil {
+			return
+		}
+		paths := make(map[*Account]string) // full path of accounts, set when visiting their parent
+		b.Root.WalkBFS(func(act *Account) bool {
+			b.byID[act.ID] = act
+			b.byName[act.Name] = append(b.byName[act.Name], act)
+			b.byType[act.Type] = append(b.byType[act.Type], act)
+			if path, ok := paths[act]; ok {
+				act.Path = path
+				b.byPath[path] = act
+			}
+			for _, child := range act.Children {
+				if act == b.Root {
+					paths[child] = child.Name
+				} else {
+					paths[child] = paths[act] + PathSeparator + child.Name
+				}
+			}
+			return false
+		})
+	})
+}
+
+// Accounts returns all the accounts of the book except the root, in Breadth-first order
+func (b *Book) Accounts() []*Account {
+	b.index()
+	if b.Root == nil {
+		return make([]*Account, 0)
+	}
+	return b.Root.Descendants()
+}
+
+// Account returns the account matching ID
+func (b *Book) Account(ID string) *Account {
+	b.index()
+	return b.byID[ID]
+}
+
+// AccountsByName returns the list of accounts matching name
+func (b *Book) AccountsByName(name string) []*Account {
+	b.index()
+	return nonNil(b.byName[name])
+}
+
+// AccountsByType returns the list of accounts matching type
+func (b *Book) AccountsByType(atype string) []*Account {
+	b.index()
+	return nonNil(b.byType[atype])
+}
+
+// AccountByPath returns the account matching its full path, like "Expenses:Auto:Fuel"
+func (b *Book) AccountByPath(path string) *Account {
+	b.index()
+	return b.byPath[path]
+}
+
+// AccountTypes returns the number of accounts for each type
+func (b *Book) AccountTypes() map[string]int {
+	b.index()
+	types := make(map[string]int, len(b.byType))
+	for t, acts := range b.byType {
+		types[t] = len(acts)
+	}
+	return types
+}
+
+func nonNil(acts []*Account) []*Account {
+	if acts == nil {
+		return make([]*Account, 0)
+	}
+	return acts
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookIndexes(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	root := book.Root
+	assert.Equal(t, root, book.Account(root.ID), "Problem while retrieve account by ID")
+	assert.Nil(t, book.Account("not existing"), "Problem while retrieve not existing account by ID")
+
+	fuels := book.AccountsByName("Fuel")
+	assert.Equal(t, root.FindByName("Fuel"), fuels, "Problem while retrieve accounts by name")
+	assert.Equal(t, 2, len(fuels), "Problem with the number of accounts by name")
+	assert.Equal(t, 0, len(book.AccountsByName("not existing")), "Problem while retrieve not existing accounts by name")
+
+	assert.Equal(t, root.FindByType("EXPENSE"), book.AccountsByType("EXPENSE"), "Problem while retrieve accounts by type")
+	assert.Equal(t, 1, book.AccountTypes()["STOCK"], "Problem with the number of accounts by type")
+
+	fuel := book.AccountByPath("Expenses:Auto:Fuel")
+	if assert.NotNil(t, fuel, "Problem while retrieve account by path") {
+		assert.Equal(t, "Auto", fuel.Parent.Name, "Problem with account retrieved by path")
+		assert.Equal(t, "Expenses:Auto:Fuel", fuel.Path, "Problem with path of account")
+	}
+	assert.Equal(t, "Assets", book.AccountByPath("Assets").Name, "Problem while retrieve top level account by path")
+	assert.Nil(t, book.AccountByPath("Expenses:Fuel"), "Problem while retrieve not existing account by path")
+	assert.Equal(t, "", root.Path, "Problem with path of root account")
+	assert.Equal(t, len(root.Descendants()), len(book.Accounts()), "Problem with the list of accounts")
+}
+
+func TestPathSeparator(t *testing.T) {
+	defer func(sep string) { PathSeparator = sep }(PathSeparator)
+	PathSeparator = "/"
+
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if !assert.NoError(t, err) {
+		return
+	}
+	fuel := book.AccountByPath("Expenses/Home/Fuel")
+	if assert.NotNil(t, fuel, "Problem while retrieve account by path with another separator") {
+		assert.Equal(t, "Expenses/Home/Fuel", fuel.Path, "Problem with path of account with another separator")
+	}
+	assert.Nil(t, book.AccountByPath("Expenses:Home:Fuel"), "Problem while retrieve account by path with default separator")
+}
+
+// generateBook returns a book with nacts accounts and ntrns transactions of 2 splits
+func generateBook(nacts int, ntrns int) *Book {
+	root := &Account{ID: "root", Name: "Root Account", Type: "ROOT"}
+	types := []string{"ASSET", "BANK", "EXPENSE", "INCOME", "LIABILITY"}
+	acts := make([]*Account, 0, nacts)
+	parents := []*Account{root}
+	for len(acts) < nacts {
+		next := make([]*Account, 0)
+		for _, parent := range parents {
+			for i := 0; i < 10 && len(acts) < nacts; i++ {
+				act := &Account{
+					ID:     fmt.Sprintf("%032x", len(acts)),
+					Name:   fmt.Sprintf("Account %d", i),
+					Type:   types[len(acts)%len(types)],
+					Parent: parent,
+				}
+				parent.Children = append(parent.Children, act)
+				acts = append(acts, act)
+				next = append(next, act)
+			}
+		}
+		parents = next
+	}
+
+	trns := make(Transactions, 0, ntrns)
+	for i := 0; i < ntrns; i++ {
+		t := &Transaction{
+			ID:         fmt.Sprintf("%032x", i),
+			Num:        fmt.Sprintf("%d", i%3),
+			DatePosted: fmt.Sprintf("%04d-%02d-%02d", 2000+i%20, 1+i%12, 1+i%28),
+		}
+		value := NewAmount(int64(i%100000), 100)
+		for j, act := range []*Account{acts[(i*7)%len(acts)], acts[(i*13+1)%len(acts)]} {
+			if j == 1 {
+				value = value.Neg()
+			}
+			s := &Split{Transaction: t, Account: act, Value: value, Quantity: value}
+			t.Splits = append(t.Splits, s)
+			act.Splits = append(act.Splits, s)
+		}
+		trns = append(trns, t)
+	}
+	return &Book{Root: root, Transactions: trns}
+}
+
+var (
+	benchBookOnce sync.Once
+	benchBook     *Book
+)
+
+func loadBenchBook(b *testing.B) *Book {
+	benchBookOnce.Do(func() {
+		benchBook = generateBook(10000, 500000) // 1 000 000 splits
+		benchBook.index()
+	})
+	b.ResetTimer()
+	return benchBook
+}
+
+func BenchmarkFindByIDWalk(b *testing.B) {
+	book := loadBenchBook(b)
+	for i := 0; i < b.N; i++ {
+		book.Root.FindByID(fmt.Sprintf("%032x", i%10000))
+	}
+}
+
+func BenchmarkFindByIDIndex(b *testing.B) {
+	book := loadBenchBook(b)
+	for i := 0; i < b.N; i++ {
+		book.Account(fmt.Sprintf("%032x", i%10000))
+	}
+}
+
+func BenchmarkFindByNameWalk(b *testing.B) {
+	book := loadBenchBook(b)
+	for i := 0; i < b.N; i++ {
+		book.Root.FindByName(fmt.Sprintf("Account %d", i%10))
+	}
+}
+
+func BenchmarkFindByNameIndex(b *testing.B) {
+	book := loadBenchBook(b)
+	for i := 0; i < b.N; i++ {
+		book.AccountsByName(fmt.Sprintf("Account %d", i%10))
+	}
+}
+
+func BenchmarkFindByTypeWalk(b *testing.B) {
+	book := loadBenchBook(b)
+	for i := 0; i < b.N; i++ {
+		book.Root.FindByType("EXPENSE")
+	}
+}
+
+func BenchmarkFindByTypeIndex(b *testing.B) {
+	book := loadBenchBook(b)
+	for i := 0; i < b.N; i++ {
+		book.AccountsByType("EXPENSE")
+	}
+}
+
+func BenchmarkBalanceRecursive(b *testing.B) {
+	book := loadBenchBook(b)
+	acts := book.Root.Children
+	for _, act := range acts { // ledgers are built on first use
+		act.subtreeLedger()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		acts[i%len(acts)].Balance(BalanceOptions{From: "2005-01-01", To: "2015-12-31", Recursive: true})
+	}
+}
+
+func BenchmarkBalanceRecursiveScan(b *testing.B) {
+	book := loadBenchBook(b)
+	acts := book.Root.Children
+	for i := 0; i < b.N; i++ {
+		acts[i%len(acts)].Balance(BalanceOptions{From: "2005-01-01", To: "2015-12-31", Recursive: true, Type: "0"})
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// FeatureNaturalBudgetSigns is the feature of the books storing the budget amounts of credit accounts
+// (income, liabilities and equity) as negative numbers, like their balance
+const FeatureNaturalBudgetSigns = "Use natural signs in budget amounts"
+
+// BudgetPeriod is a period of a budget, from Start to End included
+type BudgetPeriod struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// BudgetAccount is the amount budgeted for an account for each period of a budget, nil if not set.
+// Like in GnuCash, amounts of credit accounts are positive.
+type BudgetAccount struct {
+	Account AccountRef `json:"account"`
+	Amounts []*Amount  `json:"amounts"`
+	Total   Amount     `json:"total"`
+}
+
+// Budget is a set of amounts planned for accounts over NumPeriods periods defined by Recurrence
+type Budget struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	NumPeriods  int             `json:"num_periods"`
+	Recurrence  Recurrence      `json:"recurrence"`
+	Periods     []BudgetPeriod  `json:"periods"`
+	Accounts    []BudgetAccount `json:"accounts"`
+}
+
+// Budgets is a list of budgets
+type Budgets []*Budget
+
+// FindByID returns the budget matching ID
+func (bs Budgets) FindByID(ID string) *Budget {
+	for _, bg := range bs {
+		if bg.ID == ID {
+			return bg
+		}
+	}
+	return nil
+}
+
+// budgetPeriods returns the periods of a budget, each one ending the day before the start of the next one
+func budgetPeriods(r Recurrence, n int) []BudgetPeriod {
+	periods := make([]BudgetPeriod, 0, n)
+	start, err := time.Parse("2006-01-02", r.Start)
+	if err != nil || n > maxPeriods {
+		return periods
+	}
+	mult := r.Multiplier
+	if mult < 1 {
+		mult = 1
+	}
+	begin, ok := r.occurrence(start, 0)
+	for k := 0; ok && k < n; k++ {
+		var next time.Time
+		if next, ok = r.occurrence(start, (k+1)*mult); !ok || r.Period == Once {
+			break
+		}
+		periods = append(periods, BudgetPeriod{Start: begin.Format("2006-01-02"), End: next.AddDate(0, 0, -1).Format("2006-01-02")})
+		begin = next
+	}
+	return periods
+}
+
+// BudgetComparison compares the budgeted amount of an account to its actual flow.
+// Variance is the amount left, Used is the actual amount in percentage of the budgeted one.
+type BudgetComparison struct {
+	Budgeted Amount   `json:"budgeted"`
+	Actual   Amount   `json:"actual"`
+	Variance Amount   `json:"variance"`
+	Used     *float64 `json:"used,omitempty"`
+}
+
+// BudgetReportLine is the comparison for an account for each period of the budget and for the whole budget
+type BudgetReportLine struct {
+	Account AccountRef         `json:"account"`
+	Type    string             `json:"type"`
+	Periods []BudgetComparison `json:"periods"`
+	Total   BudgetComparison   `json:"total"`
+}
+
+// BudgetReport compares the amounts of a budget to the flows of its accounts
+type BudgetReport struct {
+	ID       string             `json:"id"`
+	Name     string             `json:"name"`
+	Currency string             `json:"currency,omitempty"`
+	Periods  []BudgetPeriod     `json:"periods"`
+	Accounts []BudgetReportLine `json:"accounts"`
+	Unpriced []string           `json:"unpriced,omitempty"`
+}
+
+// BudgetReport compares the budgeted amounts to the flows of the accounts and their sub-accounts during each period.
+// Like in GnuCash, flows of credit accounts are shown as positive numbers.
+// With opts.Currency, budgeted amounts are converted with the prices at the end of each period.
+func (b *Book) BudgetReport(bg *Budget, opts BalanceOptions) BudgetReport {
+	report := BudgetReport{ID: bg.ID, Name: bg.Name, Periods: bg.Periods, Accounts: make([]BudgetReportLine, 0, len(bg.Accounts))}
+	if opts.Currency != nil {
+		report.Currency = opts.Currency.String()
+	}
+	opts.Recursive = true
+
+	for _, ba := range bg.Accounts {
+		act := b.Account(ba.Account.ID)
+		if act == nil {
+			continue
+		}
+		reverse := hasType(IncomeTypes, act.Type) || hasType(LiabilityTypes, act.Type) || hasType(EquityTypes, act.Type)
+		line := BudgetReportLine{Account: act.Ref(), Type: act.Type, Periods: make([]BudgetComparison, 0, len(bg.Periods))}
+		var budgeted, actual Amount
+		for i, p := range bg.Periods {
+			opts.From, opts.To = p.Start, p.End
+			balance := act.Balance(opts)
+			for _, c := range balance.Unpriced {
+				report.Unpriced = appendOnce(report.Unpriced, c)
+			}
+			a := balance.Amount
+			if reverse {
+				a = a.Neg()
+			}
+			var bgt Amount
+			if i < len(ba.Amounts) && ba.Amounts[i] != nil {
+				bgt = *ba.Amounts[i]
+			}
+			if opts.Currency != nil && act.Commodity != nil && !bgt.IsZero() {
+				converted, ok := opts.Prices.Convert(bgt, act.Commodity, opts.Currency, p.End)
+				if !ok {
+					report.Unpriced = appendOnce(report.Unpriced, act.Commodity.String())
+				}
+				bgt = converted
+			}
+			line.Periods = append(line.Periods, newBudgetComparison(bgt, a))
+			budgeted, actual = budgeted.Add(bgt), actual.Add(a)
+		}
+		line.Total = newBudgetComparison(budgeted, actual)
+		report.Accounts = append(report.Accounts, line)
+	}
+	sort.Strings(report.Unpriced)
+	return report
+}
+
+func newBudgetComparison(budgeted Amount, actual Amount) BudgetComparison {
+	if actual.SCU > budgeted.SCU {
+		budgeted = budgeted.WithSCU(actual.SCU)
+	}
+	c := BudgetComparison{Budgeted: budgeted, Actual: actual, Variance: budgeted.Sub(actual)}
+	if !budgeted.IsZero() {
+		used := math.Round(actual.Float64()/budgeted.Float64()*10000) / 100
+		c.Used = &used
+	}
+	return c
+}
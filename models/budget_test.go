@@ -0,0 +1,113 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var budgetPeriodsTests = []struct {
+	recurrence Recurrence
+	n          int
+	periods    []BudgetPeriod
+}{
+	{Recurrence{Period: Monthly, Multiplier: 1, Start: "2019-01-01"}, 2, []BudgetPeriod{{"2019-01-01", "2019-01-31"}, {"2019-02-01", "2019-02-28"}}},
+	{Recurrence{Period: Monthly, Multiplier: 3, Start: "2019-01-01"}, 2, []BudgetPeriod{{"2019-01-01", "2019-03-31"}, {"2019-04-01", "2019-06-30"}}},
+	{Recurrence{Period: Weekly, Multiplier: 1, Start: "2019-06-03"}, 1, []BudgetPeriod{{"2019-06-03", "2019-06-09"}}},
+	{Recurrence{Period: Yearly, Multiplier: 1, Start: "2019-01-01"}, 1, []BudgetPeriod{{"2019-01-01", "2019-12-31"}}},
+	{Recurrence{Period: "fortnight", Multiplier: 1, Start: "2019-01-01"}, 2, []BudgetPeriod{}},
+}
+
+func TestBudgetPeriods(t *testing.T) {
+	for _, tt := range budgetPeriodsTests {
+		assert.Equal(t, tt.periods, budgetPeriods(tt.recurrence, tt.n), "Problem with periods of %v", tt.recurrence)
+	}
+}
+
+func TestLoadBudgets(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Equal(t, 1, len(book.Budgets), "Problem with the number of budgets") {
+		return
+	}
+	bg := book.Budgets[0]
+	assert.Equal(t, bg, book.Budgets.FindByID(bg.ID), "Problem while retrieving budget by ID")
+	assert.Nil(t, book.Budgets.FindByID("666"), "Problem while retrieving not existing budget")
+	assert.Equal(t, "Household 2019", bg.Name, "Problem with name of budget")
+	assert.Equal(t, 12, bg.NumPeriods, "Problem with number of periods of %s", bg.Name)
+	assert.Equal(t, 12, len(bg.Periods), "Problem with periods of %s", bg.Name)
+	if assert.Equal(t, 4, len(bg.Accounts), "Problem with accounts of %s", bg.Name) {
+		salary := bg.Accounts[0]
+		assert.Equal(t, "Salary", salary.Account.Name, "Problem with order of budget accounts")
+		assert.Equal(t, "2000.00", salary.Amounts[0].String(), "Problem with budgeted amount of %s", salary.Account.Name)
+		assert.Nil(t, salary.Amounts[3], "Problem with amount not budgeted for %s", salary.Account.Name)
+		assert.Equal(t, "6000.00", salary.Total.String(), "Problem with total budgeted for %s", salary.Account.Name)
+	}
+
+	// books with natural signs store budgets of income accounts as negative numbers
+	data, err := ioutil.ReadFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	natural := strings.Replace(string(data), "<slot:value type=\"numeric\">2000/1</slot:value>", "<slot:value type=\"numeric\">-2000/1</slot:value>", -1)
+	natural = strings.Replace(natural, "</book:id>", `</book:id>
+<book:slots>
+  <slot>
+    <slot:key>features</slot:key>
+    <slot:value type="frame">
+      <slot>
+        <slot:key>Use natural signs in budget amounts</slot:key>
+        <slot:value type="string">Use natural signs in budget amounts</slot:value>
+      </slot>
+    </slot:value>
+  </slot>
+</book:slots>`, 1)
+	book, err = Load(strings.NewReader(natural))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "6000.00", book.Budgets[0].Accounts[0].Total.String(), "Problem with budget of a book using natural signs")
+}
+
+func TestBudgetReport(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := book.BudgetReport(book.Budgets[0], BalanceOptions{})
+	assert.Equal(t, 12, len(report.Periods), "Problem with periods of budget report")
+	if !assert.Equal(t, 4, len(report.Accounts), "Problem with accounts of budget report") {
+		return
+	}
+
+	salary := report.Accounts[0]
+	assert.Equal(t, "2000.00", salary.Periods[0].Actual.String(), "Income should be shown as positive numbers")
+	assert.Equal(t, "2000.00", salary.Periods[2].Variance.String(), "Problem with variance of %s", salary.Account.Name)
+	assert.Equal(t, "4000.00", salary.Total.Actual.String(), "Problem with total of %s", salary.Account.Name)
+	if assert.NotNil(t, salary.Total.Used, "Problem with percentage used") {
+		assert.Equal(t, 66.67, *salary.Total.Used, "Problem with percentage used of %s", salary.Account.Name)
+	}
+	assert.Nil(t, salary.Periods[3].Used, "Percentage used should not be set without budget")
+
+	auto := report.Accounts[2]
+	assert.Equal(t, "Auto", auto.Account.Name, "Problem with order of budget report")
+	assert.Equal(t, "60.00", auto.Periods[0].Actual.String(), "Actual amounts should include sub-accounts")
+	assert.Equal(t, "-10.00", auto.Periods[0].Variance.String(), "Problem with variance over budget")
+	if assert.NotNil(t, auto.Periods[0].Used, "Problem with percentage used") {
+		assert.Equal(t, 120.0, *auto.Periods[0].Used, "Problem with percentage used of %s", auto.Account.Name)
+	}
+
+	usd := book.Commodities.Find(CurrencySpace, "USD")
+	report = book.BudgetReport(book.Budgets[0], BalanceOptions{Currency: usd, Prices: book.Prices})
+	assert.Equal(t, "CURRENCY:USD", report.Currency, "Problem with currency of budget report")
+	assert.Equal(t, "2240.00", report.Accounts[0].Periods[0].Actual.String(), "Problem with converted actual amount")
+	assert.Equal(t, "2240.00", report.Accounts[0].Periods[0].Budgeted.String(), "Problem with converted budgeted amount")
+	assert.Empty(t, report.Unpriced, "Problem with unpriced commodities")
+}
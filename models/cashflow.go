@@ -0,0 +1,116 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// CashFlowLine is the money received from or paid to a counterpart account
+type CashFlowLine struct {
+	Account AccountRef `json:"account"`
+	Amount  Amount     `json:"amount"`
+}
+
+// CashFlow is the money coming in and going out of a set of accounts during a period,
+// broken down by counterpart account. Transfers between the accounts of the set are ignored.
+type CashFlow struct {
+	From     string         `json:"from,omitempty"`
+	To       string         `json:"to"`
+	Currency string         `json:"currency,omitempty"`
+	Accounts []AccountRef   `json:"accounts"`
+	MoneyIn  []CashFlowLine `json:"money_in"`
+	MoneyOut []CashFlowLine `json:"money_out"`
+	TotalIn  Amount         `json:"total_in"`
+	TotalOut Amount         `json:"total_out"`
+	NetFlow  Amount         `json:"net_flow"`
+	Unpriced []string       `json:"unpriced,omitempty"`
+}
+
+// CashFlow returns the cash flow of accounts between opts.From and opts.To,
+// by default of all the accounts of CashTypes.
+// With opts.Currency, amounts are converted at the date of their transaction.
+func (b *Book) CashFlow(accounts []*Account, opts BalanceOptions) CashFlow {
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+	if len(accounts) == 0 {
+		for _, t := range CashTypes {
+			accounts = append(accounts, b.AccountsByType(t)...)
+		}
+	}
+
+	cf := CashFlow{From: opts.From, To: opts.To, Accounts: make([]AccountRef, 0, len(accounts))}
+	if opts.Currency != nil {
+		cf.Currency = opts.Currency.String()
+	}
+	selected := make(map[*Account]bool, len(accounts))
+	for _, act := range accounts {
+		selected[act] = true
+		cf.Accounts = append(cf.Accounts, act.Ref())
+	}
+
+	in := make(map[*Account]Amount)
+	out := make(map[*Account]Amount)
+	for _, t := range b.Transactions {
+		if t.DatePosted < opts.From || t.DatePosted > opts.To {
+			continue
+		}
+		touched := false
+		for _, s := range t.Splits {
+			if selected[s.Account] {
+				touched = true
+				break
+			}
+		}
+		if !touched {
+			continue
+		}
+
+		for _, s := range t.Splits {
+			if selected[s.Account] || s.Account == nil {
+				continue
+			}
+			value := s.Value
+			if opts.Currency != nil && t.Currency != nil && !value.IsZero() {
+				converted, ok := opts.Prices.Convert(value, t.Currency, opts.Currency, t.DatePosted)
+				if !ok {
+					cf.Unpriced = appendOnce(cf.Unpriced, t.Currency.String())
+					continue
+				}
+				value = converted
+			}
+			switch value.Sign() {
+			case -1: // the counterpart is credited, money comes in
+				in[s.Account] = in[s.Account].Add(value.Neg())
+			case 1:
+				out[s.Account] = out[s.Account].Add(value)
+			}
+		}
+	}
+
+	cf.MoneyIn, cf.TotalIn = cashFlowLines(in)
+	cf.MoneyOut, cf.TotalOut = cashFlowLines(out)
+	cf.NetFlow = cf.TotalIn.Sub(cf.TotalOut)
+	sort.Strings(cf.Unpriced)
+	return cf
+}
+
+// cashFlowLines returns the amounts by account sorted from the largest, and their total
+func cashFlowLines(amounts map[*Account]Amount) ([]CashFlowLine, Amount) {
+	lines := make([]CashFlowLine, 0, len(amounts))
+	var total Amount
+	for act, amount := range amounts {
+		lines = append(lines, CashFlowLine{Account: act.Ref(), Amount: amount})
+		total = total.Add(amount)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if c := lines[i].Amount.Cmp(lines[j].Amount); c != 0 {
+			return c > 0
+		}
+		return lines[i].Account.ID < lines[j].Account.ID
+	})
+	return lines, total
+}
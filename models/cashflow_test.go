@@ -0,0 +1,45 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCashFlow(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cf := book.CashFlow(nil, BalanceOptions{From: "2019-01-01", To: "2019-12-31"})
+	assert.Equal(t, 2, len(cf.Accounts), "Problem with the default accounts of cash flow")
+	assert.Equal(t, "5340.00", cf.TotalIn.String(), "Problem with total money in")
+	assert.Equal(t, "1165.20", cf.TotalOut.String(), "Problem with total money out")
+	assert.Equal(t, "4174.80", cf.NetFlow.String(), "Problem with net flow")
+	if assert.Equal(t, 4, len(cf.MoneyIn), "Problem with the number of money in lines") {
+		assert.Equal(t, "Salary", cf.MoneyIn[0].Account.Name, "Problem with order of money in lines")
+		assert.Equal(t, "4000.00", cf.MoneyIn[0].Amount.String(), "Problem with money in from %s", cf.MoneyIn[0].Account.Name)
+		assert.Equal(t, "AAPL", cf.MoneyIn[2].Account.Name, "Problem with order of money in lines")
+	}
+	for _, l := range cf.MoneyOut {
+		assert.NotEqual(t, "US Bank", l.Account.Name, "Transfers between selected accounts should be ignored")
+	}
+
+	checking := book.AccountByPath("Assets:Checking Account")
+	cf = book.CashFlow([]*Account{checking}, BalanceOptions{From: "2019-01-01", To: "2019-01-31"})
+	if assert.Equal(t, 1, len(cf.MoneyOut), "Problem with the number of money out lines") {
+		assert.Equal(t, "US Bank", cf.MoneyOut[0].Account.Name, "Problem with money out")
+		assert.Equal(t, "1000.00", cf.MoneyOut[0].Amount.String(), "Problem with money out to %s", cf.MoneyOut[0].Account.Name)
+	}
+	assert.Equal(t, "3000.00", cf.TotalIn.String(), "Problem with total money in")
+
+	eur := book.Commodities.Find(CurrencySpace, "EUR")
+	cf = book.CashFlow(nil, BalanceOptions{From: "2019-02-01", To: "2019-02-01", Currency: eur, Prices: book.Prices})
+	assert.Equal(t, "CURRENCY:EUR", cf.Currency, "Problem with cash flow currency")
+	assert.Equal(t, "669.64", cf.TotalOut.String(), "Problem with converted money out")
+	assert.Empty(t, cf.Unpriced, "Problem with unpriced commodities")
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+// Commodity is anything that can be held in an account: a currency, a stock, a fund...
+// A commodity is uniquely identified by its namespace (Space) and its ID.
+type Commodity struct {
+	Space       string `json:"space"`
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Fraction    int64  `json:"fraction,omitempty"`
+	XCode       string `json:"xcode,omitempty"`
+	QuoteSource string `json:"quote_source,omitempty"`
+}
+
+// CurrencySpace is the namespace of ISO 4217 currencies
+const CurrencySpace = "CURRENCY"
+
+// IsCurrency reports whether the commodity is a currency
+func (c *Commodity) IsCurrency() bool {
+	return c.Space == CurrencySpace
+}
+
+// String returns the commodity in the "SPACE:ID" form
+func (c *Commodity) String() string {
+	return c.Space + ":" + c.ID
+}
+
+// Commodities is the list of the commodities used in a book
+type Commodities []*Commodity
+
+// Find returns the commodity matching space and id
+func (cs Commodities) Find(space string, id string) *Commodity {
+	for _, c := range cs {
+		if c.Space == space && c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// FindBySpace returns the list of commodities in a namespace
+func (cs Commodities) FindBySpace(space string) Commodities {
+	found := make(Commodities, 0)
+	for _, c := range cs {
+		if c.Space == space {
+			found = append(found, c)
+		}
+	}
+	return found
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ForecastEntry is an occurrence of a scheduled transaction affecting the forecasted account,
+// with the projected balance after it.
+// Overdue is set for an occurrence due before the start of the forecast but not yet created.
+// Unpriced lists the commodities without price met up to this entry, the balance being unknown when it is not empty.
+type ForecastEntry struct {
+	Date        string   `json:"date"`
+	Scheduled   string   `json:"scheduled"` // ID of the scheduled transaction
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Overdue     bool     `json:"overdue,omitempty"`
+	Amount      Amount   `json:"amount"`
+	Balance     Amount   `json:"balance"`
+	Unpriced    []string `json:"unpriced,omitempty"`
+}
+
+// Forecast is the projection of the balance of an account from Date until Until,
+// applying the occurrences of the scheduled transactions not yet created.
+// Lowest is the lowest projected balance, reached first on LowestDate, among the known balances.
+type Forecast struct {
+	Account    AccountRef      `json:"account"`
+	Date       string          `json:"date"`
+	Until      string          `json:"until"`
+	Currency   string          `json:"currency,omitempty"`
+	Balance    Amount          `json:"balance"`
+	Entries    []ForecastEntry `json:"entries"`
+	Series     []Balance       `json:"series"`
+	Lowest     Amount          `json:"lowest"`
+	LowestDate string          `json:"lowest_date"`
+	Unpriced   []string        `json:"unpriced,omitempty"`
+}
+
+// Forecast projects the balance of the account at opts.To (default today) up to until (default one month later),
+// with the balance at the end of each period of interval. The overdue occurrences are applied first.
+// The balance and the occurrences are in opts.Currency, or in the commodity of the account when no currency is set,
+// converted with the prices known at opts.To. From the first commodity without price, the balances are unknown
+// and listed in Unpriced of each entry and each point of the series.
+func (b *Book) Forecast(a *Account, opts BalanceOptions, until string, interval string) (Forecast, error) {
+	if opts.Quantity {
+		return Forecast{}, errors.New("scheduled transactions can not be forecast by quantity")
+	}
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+	if until == "" {
+		if d, err := time.Parse("2006-01-02", opts.To); err == nil {
+			until = d.AddDate(0, 1, 0).Format("2006-01-02")
+		}
+	}
+	ends, err := PeriodEnds(opts.To, until, interval)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	opts.From = ""
+	if opts.Currency == nil {
+		opts.Currency, opts.Prices = a.Commodity, b.Prices
+	}
+	current := a.Balance(opts)
+	f := Forecast{
+		Account:    a.Ref(),
+		Date:       opts.To,
+		Until:      until,
+		Currency:   current.Currency,
+		Balance:    current.Amount,
+		Entries:    make([]ForecastEntry, 0),
+		Series:     make([]Balance, 0, len(ends)),
+		Lowest:     current.Amount,
+		LowestDate: opts.To,
+		Unpriced:   current.Unpriced,
+	}
+
+	ids := map[string]bool{a.ID: true}
+	if opts.Recursive {
+		for _, act := range a.Descendants() {
+			ids[act.ID] = true
+		}
+	}
+	running := current.Amount
+	for _, o := range b.pending(until) {
+		if opts.Type != "" && o.Num != opts.Type {
+			continue
+		}
+		var amount Amount
+		found := false
+		for _, s := range o.Splits {
+			if ids[s.Account.ID] {
+				amount = amount.Add(s.Amount)
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+		if opts.Currency != nil && o.Currency != nil && o.Currency != opts.Currency {
+			converted, ok := opts.Prices.Convert(amount, o.Currency, opts.Currency, opts.To)
+			if !ok {
+				f.Unpriced = appendOnce(f.Unpriced, o.Currency.String())
+				sort.Strings(f.Unpriced)
+			}
+			amount = converted
+		}
+		running = running.Add(amount)
+		entry := ForecastEntry{
+			Date:        o.Date,
+			Scheduled:   o.Scheduled,
+			Name:        o.Name,
+			Description: o.Description,
+			Overdue:     o.Date < opts.To,
+			Amount:      amount,
+			Balance:     running,
+		}
+		if len(f.Unpriced) > 0 {
+			entry.Unpriced = append([]string{}, f.Unpriced...)
+		} else if running.Cmp(f.Lowest) < 0 {
+			f.Lowest, f.LowestDate = running, o.Date
+			if entry.Overdue {
+				f.LowestDate = opts.To
+			}
+		}
+		f.Entries = append(f.Entries, entry)
+	}
+
+	e := 0
+	balance, unpriced := current.Amount, current.Unpriced
+	for _, end := range ends {
+		for ; e < len(f.Entries) && f.Entries[e].Date <= end; e++ {
+			balance, unpriced = f.Entries[e].Balance, f.Entries[e].Unpriced
+		}
+		f.Series = append(f.Series, Balance{Date: end, Value: balance.Float64(), Amount: balance, Currency: f.Currency, Unpriced: unpriced})
+	}
+	return f, nil
+}
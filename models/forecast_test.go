@@ -0,0 +1,153 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecast(t *testing.T) {
+	book, err := LoadFromFile("testdata/empty.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checking := book.Account("6536691459e4412fa4f182ba23562efe")
+	book.Scheduled = append(book.Scheduled, &ScheduledTransaction{
+		ID:          "rent",
+		Name:        "Rent",
+		Enabled:     true,
+		Start:       "2019-01-01",
+		Last:        "2019-06-05",
+		Recurrences: []Recurrence{{Period: Monthly, Multiplier: 1, Start: "2019-01-05"}},
+		Templates: []ScheduledTemplate{{
+			Description: "Rent",
+			Splits: []ScheduledSplit{
+				{Account: AccountRef{ID: checking.ID}, Amount: NewAmount(-100000, 100)},
+				{Account: AccountRef{ID: "expenses"}, Amount: NewAmount(100000, 100)},
+			},
+		}},
+	})
+
+	f, err := book.Forecast(checking, BalanceOptions{To: "2019-06-30", Recursive: true}, "2019-08-31", Month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "918.05", f.Balance.String(), "Problem with starting balance of forecast")
+	if assert.Equal(t, 4, len(f.Entries), "Problem with the number of forecast entries") {
+		assert.Equal(t, "2019-07-05", f.Entries[0].Date, "Problem with date of first entry")
+		assert.Equal(t, "-1000.00", f.Entries[0].Amount.String(), "Problem with amount of first entry")
+		assert.Equal(t, "-81.95", f.Entries[0].Balance.String(), "Problem with balance after first entry")
+		assert.Equal(t, "Netflix", f.Entries[1].Name, "Problem with order of entries")
+		assert.Equal(t, "-68.95", f.Entries[1].Balance.String(), "Problem with balance after second entry")
+	}
+	assert.Equal(t, "-1068.95", f.Lowest.String(), "Problem with lowest balance")
+	assert.Equal(t, "2019-08-05", f.LowestDate, "Problem with date of lowest balance")
+	if assert.Equal(t, 3, len(f.Series), "Problem with the number of periods") {
+		assert.Equal(t, "918.05", f.Series[0].Amount.String(), "Problem with balance at %s", f.Series[0].Date)
+		assert.Equal(t, "-68.95", f.Series[1].Amount.String(), "Problem with balance at %s", f.Series[1].Date)
+		assert.Equal(t, "-1055.95", f.Series[2].Amount.String(), "Problem with balance at %s", f.Series[2].Date)
+	}
+
+	// occurrences due before the start of the forecast but not yet created are applied first
+	book.Scheduled[len(book.Scheduled)-1].Last = "2019-05-05"
+	f, err = book.Forecast(checking, BalanceOptions{To: "2019-06-30", Recursive: true}, "2019-07-31", Month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 3, len(f.Entries), "Problem with the number of forecast entries") {
+		assert.Equal(t, "2019-06-05", f.Entries[0].Date, "Problem with date of overdue entry")
+		assert.True(t, f.Entries[0].Overdue, "Problem with overdue entry")
+		assert.False(t, f.Entries[1].Overdue, "Problem with entry not overdue")
+		assert.Equal(t, "-81.95", f.Entries[0].Balance.String(), "Problem with balance after overdue entry")
+	}
+	assert.Equal(t, "-81.95", f.Series[0].Amount.String(), "Problem with balance at %s", f.Series[0].Date)
+	assert.Equal(t, "2019-07-05", f.LowestDate, "Problem with date of lowest balance")
+	book.Scheduled[len(book.Scheduled)-1].Last = "2019-06-05"
+
+	// the parent of checking is affected through its sub-tree only
+	f, err = book.Forecast(checking.Parent, BalanceOptions{To: "2019-06-30"}, "2019-08-31", Month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, f.Entries, "Scheduled transactions of sub-accounts should be ignored without recursion")
+
+	_, err = book.Forecast(checking, BalanceOptions{To: "2019-06-30"}, "2019-05-31", Month)
+	assert.Error(t, err, "A forecast should not end before its start")
+
+	_, err = book.Forecast(checking, BalanceOptions{To: "2019-06-30", Quantity: true}, "2019-08-31", Month)
+	assert.Error(t, err, "A forecast should not sum quantities")
+}
+
+func TestForecastOtherCurrency(t *testing.T) {
+	book, err := LoadFromFile("testdata/empty.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checking := book.Account("6536691459e4412fa4f182ba23562efe")
+	usd := &Commodity{Space: CurrencySpace, ID: "USD", Fraction: 100}
+	book.Scheduled = []*ScheduledTransaction{{
+		ID:          "subscription",
+		Name:        "Subscription",
+		Enabled:     true,
+		Start:       "2019-01-01",
+		Last:        "2019-06-15",
+		Recurrences: []Recurrence{{Period: Monthly, Multiplier: 1, Start: "2019-01-15"}},
+		Templates: []ScheduledTemplate{{
+			Description: "Subscription",
+			Currency:    usd,
+			Splits: []ScheduledSplit{
+				{Account: AccountRef{ID: checking.ID}, Amount: NewAmount(-1000, 100)},
+				{Account: AccountRef{ID: "expenses"}, Amount: NewAmount(1000, 100)},
+			},
+		}},
+	}}
+
+	// without price, the balances in EUR after an amount in USD are unknown
+	book.Prices = NewPriceDB(nil)
+	f, err := book.Forecast(checking, BalanceOptions{To: "2019-06-30", Recursive: true}, "2019-07-31", Month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 1, len(f.Entries), "Problem with the number of forecast entries") {
+		assert.True(t, f.Entries[0].Amount.IsZero(), "Problem with amount of an entry without price")
+		assert.Equal(t, []string{"CURRENCY:USD"}, f.Entries[0].Unpriced, "Problem with unpriced commodities of an entry")
+	}
+	assert.Equal(t, []string{"CURRENCY:USD"}, f.Unpriced, "Problem with unpriced commodities")
+	if assert.Equal(t, 2, len(f.Series), "Problem with the number of periods") {
+		assert.Empty(t, f.Series[0].Unpriced, "Problem with unpriced commodities before the entry without price")
+		assert.Equal(t, []string{"CURRENCY:USD"}, f.Series[1].Unpriced, "Problem with unpriced commodities after the entry without price")
+	}
+	assert.Equal(t, "918.05", f.Lowest.String(), "Problem with lowest balance ignoring unknown balances")
+
+	book.Prices = NewPriceDB([]*Price{{Commodity: usd, Currency: checking.Commodity, Date: "2019-06-01", Value: NewAmount(90, 100)}})
+	f, err = book.Forecast(checking, BalanceOptions{To: "2019-06-30", Recursive: true}, "2019-07-31", Month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 1, len(f.Entries), "Problem with the number of forecast entries") {
+		assert.Equal(t, "-9.00", f.Entries[0].Amount.String(), "Problem with amount converted to the commodity of the account")
+		assert.Equal(t, "909.05", f.Entries[0].Balance.String(), "Problem with balance after a converted entry")
+	}
+	assert.Empty(t, f.Unpriced, "Problem with unpriced commodities")
+}
+
+func TestForecastAccountCommodity(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	book.Scheduled = nil
+
+	// the starting balance is in the commodity of the account, like the occurrences
+	assets := book.Account("9aedeaf1f77b8642abe528503b8c5de8")
+	f, err := book.Forecast(assets, BalanceOptions{To: "2019-12-31", Recursive: true}, "2020-01-31", Month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "CURRENCY:EUR", f.Currency, "Problem with currency of forecast")
+	assert.Equal(t, "4664.45", f.Balance.String(), "Problem with starting balance of forecast")
+	assert.Empty(t, f.Unpriced, "Problem with unpriced commodities")
+}
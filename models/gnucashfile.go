@@ -10,6 +10,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,52 +21,247 @@ type xmlCountData struct {
 	Value int    `xml:",chardata"`
 }
 
+type xmlCommodity struct {
+	Space       string `xml:"space"`
+	ID          string `xml:"id"`
+	Name        string `xml:"name"`
+	XCode       string `xml:"xcode"`
+	Fraction    int64  `xml:"fraction"`
+	QuoteSource string `xml:"quote_source"`
+}
+
 type xmlAccount struct {
-	Name     string `xml:"name"`
-	ID       string `xml:"id"`
-	Type     string `xml:"type"`
-	ParentID string `xml:"parent"`
-	Parent   *xmlAccount
-	Children []*xmlAccount
+	Name        string       `xml:"name"`
+	ID          string       `xml:"id"`
+	Type        string       `xml:"type"`
+	Commodity   xmlCommodity `xml:"commodity"`
+	ParentID    string       `xml:"parent"`
+	SCU         int64        `xml:"commodity-scu"`
+	Code        string       `xml:"code"`
+	Description string       `xml:"description"`
+	Slots       []xmlSlot    `xml:"slots>slot"`
+	Lots        []xmlLot     `xml:"lots>lot"`
+	Parent      *xmlAccount
+	Children    []*xmlAccount
+}
+
+type xmlLot struct {
+	ID    string    `xml:"id"`
+	Slots []xmlSlot `xml:"slots>slot"`
+}
+
+type xmlPriceDB struct {
+	Prices []xmlPrice `xml:"price"`
+}
+
+type xmlPrice struct {
+	Commodity xmlCommodity `xml:"commodity"`
+	Currency  xmlCommodity `xml:"currency"`
+	Time      string       `xml:"time>date"`
+	Source    string       `xml:"source"`
+	Type      string       `xml:"type"`
+	Value     string       `xml:"value"`
 }
 
 type xmlTransaction struct {
-	Num        string     `xml:"num"`
-	DatePosted string     `xml:"date-posted>date"`
-	Splits     []xmlSplit `xml:"splits>split"`
+	ID          string       `xml:"id"`
+	Currency    xmlCommodity `xml:"currency"`
+	Num         string       `xml:"num"`
+	DatePosted  string       `xml:"date-posted>date"`
+	DateEntered string       `xml:"date-entered>date"`
+	Description string       `xml:"description"`
+	Slots       []xmlSlot    `xml:"slots>slot"`
+	Splits      []xmlSplit   `xml:"splits>split"`
 }
 
 type xmlSplit struct {
-	Value   string `xml:"value"`
-	Account string `xml:"account"`
+	ID              string    `xml:"id"`
+	Memo            string    `xml:"memo"`
+	Action          string    `xml:"action"`
+	ReconciledState string    `xml:"reconciled-state"`
+	ReconcileDate   string    `xml:"reconcile-date>date"`
+	Value           string    `xml:"value"`
+	Quantity        string    `xml:"quantity"`
+	Account         string    `xml:"account"`
+	Lot             string    `xml:"lot"`
+	Slots           []xmlSlot `xml:"slots>slot"`
+}
+
+type xmlTemplateTransactions struct {
+	Accounts     []xmlAccount     `xml:"account"`
+	Transactions []xmlTransaction `xml:"transaction"`
+}
+
+type xmlScheduledTransaction struct {
+	ID              string          `xml:"id"`
+	Name            string          `xml:"name"`
+	Enabled         string          `xml:"enabled"`
+	AutoCreate      string          `xml:"autoCreate"`
+	Start           string          `xml:"start>gdate"`
+	End             string          `xml:"end>gdate"`
+	Last            string          `xml:"last>gdate"`
+	NumOccur        int             `xml:"num-occur"`
+	RemOccur        int             `xml:"rem-occur"`
+	TemplateAccount string          `xml:"templ-acct"`
+	Recurrences     []xmlRecurrence `xml:"schedule>recurrence"`
+}
+
+type xmlRecurrence struct {
+	Mult          int    `xml:"mult"`
+	PeriodType    string `xml:"period_type"`
+	Start         string `xml:"start>gdate"`
+	WeekendAdjust string `xml:"weekend_adj"`
+}
+
+type xmlBudget struct {
+	ID          string        `xml:"id"`
+	Name        string        `xml:"name"`
+	Description string        `xml:"description"`
+	NumPeriods  int           `xml:"num-periods"`
+	Recurrence  xmlRecurrence `xml:"recurrence"`
+	Slots       []xmlSlot     `xml:"slots>slot"`
+}
+
+type xmlSlot struct {
+	Key   string       `xml:"key"`
+	Value xmlSlotValue `xml:"value"`
+}
+
+type xmlSlotValue struct {
+	Type   string         `xml:"type,attr"`
+	Text   string         `xml:",chardata"`
+	Slots  []xmlSlot      `xml:"slot"`  // frame
+	Values []xmlSlotValue `xml:"value"` // list
+	Date   string         `xml:"date"`  // timespec
+	GDate  string         `xml:"gdate"` // gdate
+}
+
+// slotValue returns the value of the slot key in slots
+func slotValue(slots []xmlSlot, key string) string {
+	for _, s := range slots {
+		if s.Key == key {
+			return strings.TrimSpace(s.Value.Text)
+		}
+	}
+	return ""
+}
+
+// newSlots converts a frame of slots, nil if there is no slot
+func newSlots(xslots []xmlSlot) Slots {
+	if len(xslots) == 0 {
+		return nil
+	}
+	slots := make(Slots, len(xslots))
+	for _, xs := range xslots {
+		slots[xs.Key] = newSlot(xs.Value)
+	}
+	return slots
+}
+
+// newSlot converts a slot value to its type, keeping the text when it can not be parsed
+func newSlot(xv xmlSlotValue) Slot {
+	text := strings.TrimSpace(xv.Text)
+	slot := Slot{Type: xv.Type, Value: text}
+	switch xv.Type {
+	case SlotInteger:
+		if v, err := strconv.ParseInt(text, 10, 64); err == nil {
+			slot.Value = v
+		}
+	case SlotDouble:
+		if v, err := strconv.ParseFloat(text, 64); err == nil {
+			slot.Value = v
+		}
+	case SlotNumeric:
+		if v, err := ParseAmount(text); err == nil {
+			slot.Value = v
+		}
+	case SlotTimespec:
+		slot.Value = strings.TrimSpace(xv.Date)
+	case SlotGDate:
+		slot.Value = strings.TrimSpace(xv.GDate)
+	case SlotFrame:
+		frame := newSlots(xv.Slots)
+		if frame == nil {
+			frame = make(Slots)
+		}
+		slot.Value = frame
+	case SlotList:
+		list := make([]Slot, 0, len(xv.Values))
+		for _, v := range xv.Values {
+			list = append(list, newSlot(v))
+		}
+		slot.Value = list
+	}
+	return slot
+}
+
+// slotBool returns the value of the boolean slot key in slots, written as "true" or as a number
+func slotBool(slots []xmlSlot, key string) bool {
+	switch strings.TrimSpace(slotValue(slots, key)) {
+	case "true", "1":
+		return true
+	}
+	return false
 }
 
 // LoadFromFile loads data from a GnuCash file compressed or not
-func LoadFromFile(path string) (*Account, error) {
+func LoadFromFile(path string) (*Book, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var book *Book
 	zr, err := gzip.NewReader(f)
 	switch err {
 	case nil:
 		defer zr.Close()
-		return Load(zr)
+		book, err = Load(zr)
 	case gzip.ErrHeader: // uncompressed file
 		f.Seek(0, 0)
-		return Load(f)
-	default:
+		book, err = Load(f)
+	}
+	if err != nil {
 		return nil, err
 	}
+
+	book.File = FileInfo{Path: path, ModTime: fi.ModTime(), Size: fi.Size()}
+	return book, nil
 }
 
-// Load loads GnuCash account hierarchy from a XML document
-// Returns a pointer to the root account of the hierarchy
-func Load(r io.Reader) (*Account, error) {
+// Load loads a GnuCash book from a XML document
+// Returns a pointer to the book, its accounts hierarchy starting at Root
+func Load(r io.Reader) (*Book, error) {
 	var root *Account
 	var actsIndex map[string]*Account
+	lotsIndex := make(map[string]*Lot)
+	cmdties := make(Commodities, 0)
+	prices := make([]*Price, 0)
+	trns := make(Transactions, 0)
+	unbalanced := make(Transactions, 0)
+	var bookSlots Slots
+	var templates xmlTemplateTransactions
+	xscheduled := make([]xmlScheduledTransaction, 0)
+	xbudgets := make([]xmlBudget, 0)
+
+	// commodity returns the commodity referenced by an account, registering it if not already known
+	commodity := func(ref xmlCommodity) *Commodity {
+		if ref.Space == "" && ref.ID == "" {
+			return nil
+		}
+		c := cmdties.Find(ref.Space, ref.ID)
+		if c == nil {
+			c = &Commodity{Space: ref.Space, ID: ref.ID}
+			cmdties = append(cmdties, c)
+		}
+		return c
+	}
 
 	type countData struct {
 		acts int
@@ -88,6 +284,14 @@ func Load(r io.Reader) (*Account, error) {
 
 		switch se := token.(type) {
 		case xml.StartElement:
+			if se.Name.Space == "http://www.gnucash.org/XML/book" && se.Name.Local == "slots" {
+				var xslots struct {
+					Slots []xmlSlot `xml:"slot"`
+				}
+				decoder.DecodeElement(&xslots, &se)
+				bookSlots = newSlots(xslots.Slots)
+				continue
+			}
 			if se.Name.Space != "http://www.gnucash.org/XML/gnc" {
 				continue
 			}
@@ -107,19 +311,60 @@ func Load(r io.Reader) (*Account, error) {
 				continue
 			}
 
+			if se.Name.Local == "commodity" {
+				var xcmdty xmlCommodity
+				decoder.DecodeElement(&xcmdty, &se)
+				if xcmdty.Space == "template" { // used only by scheduled transactions
+					continue
+				}
+				c := commodity(xcmdty)
+				c.Name = xcmdty.Name
+				c.XCode = xcmdty.XCode
+				c.Fraction = xcmdty.Fraction
+				c.QuoteSource = xcmdty.QuoteSource
+				continue
+			}
+
+			if se.Name.Local == "pricedb" {
+				var xpdb xmlPriceDB
+				decoder.DecodeElement(&xpdb, &se)
+				for _, xp := range xpdb.Prices {
+					value, err := ParseAmount(xp.Value)
+					if err != nil {
+						log.Printf("Invalid value '%s' for price of '%s:%s': %s", xp.Value, xp.Commodity.Space, xp.Commodity.ID, err)
+						continue
+					}
+					p := Price{
+						Commodity: commodity(xp.Commodity),
+						Currency:  commodity(xp.Currency),
+						Date:      gncDate(xp.Time),
+						Value:     value,
+						Source:    xp.Source,
+						Type:      xp.Type,
+					}
+					prices = append(prices, &p)
+				}
+				continue
+			}
+
 			if se.Name.Local == "account" {
 				var xmlact xmlAccount
 				decoder.DecodeElement(&xmlact, &se)
 				read.acts++
 
+				cmdty := commodity(xmlact.Commodity)
+				if cmdty != nil && cmdty.Fraction == 0 { // fraction is not written for ISO currencies
+					cmdty.Fraction = xmlact.SCU
+				}
+
 				// I hope Root Account is always the first account encountered
 				if root == nil {
 					if xmlact.Type == "ROOT" && xmlact.Name == "Root Account" {
-						root = &Account{ID: xmlact.ID, Name: xmlact.Name, Type: xmlact.Type}
+						root = &Account{ID: xmlact.ID, Name: xmlact.Name, Type: xmlact.Type, Commodity: cmdty, SCU: xmlact.SCU, Slots: newSlots(xmlact.Slots)}
 						actsIndex[xmlact.ID] = root
 						continue
 					}
-					return root, errors.New("Unable to initialize accounts hierarchy with Root Account")
+					return nil, errors.New("Unable to initialize accounts hierarchy with Root Account")
 				}
 
 				// Attach this node to the accounts tree
@@ -128,8 +373,33 @@ func Load(r io.Reader) (*Account, error) {
 					log.Printf("ParentID not found in index for Account '%s'", xmlact.Name)
 					continue
 				}
-				act := Account{ID: xmlact.ID, Name: xmlact.Name, Type: xmlact.Type, Parent: parent}
+				act := Account{
+					ID:          xmlact.ID,
+					Name:        xmlact.Name,
+					Type:        xmlact.Type,
+					Commodity:   cmdty,
+					SCU:         xmlact.SCU,
+					Code:        xmlact.Code,
+					Description: xmlact.Description,
+					Notes:       slotValue(xmlact.Slots, "notes"),
+					Placeholder: slotBool(xmlact.Slots, "placeholder"),
+					Hidden:      slotBool(xmlact.Slots, "hidden"),
+					TaxRelated:  slotBool(xmlact.Slots, "tax-related"),
+					Slots:       newSlots(xmlact.Slots),
+					Parent:      parent,
+				}
 				parent.Children = append(parent.Children, &act)
+				for _, xlot := range xmlact.Lots {
+					lot := &Lot{
+						ID:      xlot.ID,
+						Title:   slotValue(xlot.Slots, "title"),
+						Notes:   slotValue(xlot.Slots, "notes"),
+						Account: &act,
+						Slots:   newSlots(xlot.Slots),
+					}
+					act.Lots = append(act.Lots, lot)
+					lotsIndex[lot.ID] = lot
+				}
 
 				actsIndex[xmlact.ID] = &act
 				continue
@@ -139,24 +409,91 @@ func Load(r io.Reader) (*Account, error) {
 				var xtrn xmlTransaction
 				decoder.DecodeElement(&xtrn, &se)
 				read.trns++
-				for _, split := range xtrn.Splits {
-					act := actsIndex[split.Account]
+				trn := Transaction{
+					ID:          xtrn.ID,
+					Currency:    commodity(xtrn.Currency),
+					Num:         xtrn.Num,
+					DatePosted:  gncDate(xtrn.DatePosted),
+					DateEntered: strings.TrimSpace(xtrn.DateEntered),
+					Description: xtrn.Description,
+					Notes:       slotValue(xtrn.Slots, "notes"),
+					Slots:       newSlots(xtrn.Slots),
+				}
+				for _, xsplit := range xtrn.Splits {
+					act := actsIndex[xsplit.Account]
 					if act == nil {
-						log.Printf("Account '%s' not found in index for transaction", split.Account)
+						log.Printf("Account '%s' not found in index for transaction", xsplit.Account)
 						continue
 					}
-					trn := Transaction{
-						Num:   xtrn.Num,
-						Date:  strings.TrimSpace(strings.Split(xtrn.DatePosted, " ")[0]), // '2014-07-30 00:00:00 +0200', we keep only '2014-07-30'
-						Value: stringToFloat(split.Value),
+					value, err := ParseAmount(xsplit.Value)
+					if err != nil {
+						log.Printf("Invalid value '%s' for split in account '%s': %s", xsplit.Value, act.Name, err)
+						continue
+					}
+					quantity := value
+					if xsplit.Quantity != "" {
+						quantity, err = ParseAmount(xsplit.Quantity)
+					}
+					if err != nil {
+						log.Printf("Invalid quantity '%s' for split in account '%s': %s", xsplit.Quantity, act.Name, err)
+						continue
 					}
-					act.Transactions = append(act.Transactions, &trn)
+					valueSCU := act.SCU
+					if trn.Currency != nil && trn.Currency.Fraction > 0 {
+						valueSCU = trn.Currency.Fraction
+					}
+					split := Split{
+						ID:              xsplit.ID,
+						Transaction:     &trn,
+						Account:         act,
+						Memo:            xsplit.Memo,
+						Action:          xsplit.Action,
+						ReconciledState: xsplit.ReconciledState,
+						ReconcileDate:   gncDate(xsplit.ReconcileDate),
+						Value:           value.WithSCU(valueSCU),
+						Quantity:        quantity.WithSCU(act.SCU),
+						Slots:           newSlots(xsplit.Slots),
+					}
+					if xsplit.Lot != "" {
+						if lot := lotsIndex[xsplit.Lot]; lot != nil {
+							split.Lot = lot
+							lot.Splits = append(lot.Splits, &split)
+						} else {
+							log.Printf("Lot '%s' not found in index for split in account '%s'", xsplit.Lot, act.Name)
+						}
+					}
+					trn.Splits = append(trn.Splits, &split)
+					act.Splits = append(act.Splits, &split)
+				}
+				trns = append(trns, &trn)
+				if imbalance := trn.Imbalance(); !imbalance.IsZero() {
+					log.Printf("Transaction '%s' of %s is unbalanced by %s", trn.ID, trn.DatePosted, imbalance)
+					unbalanced = append(unbalanced, &trn)
 				}
 			}
 
-			// Skip all accounts and transactions templates used in schedule action
+			// Accounts and transactions templates used by scheduled transactions,
+			// resolved once all accounts are known
 			if se.Name.Local == "template-transactions" {
-				decoder.Skip()
+				var xtemplates xmlTemplateTransactions
+				decoder.DecodeElement(&xtemplates, &se)
+				templates.Accounts = append(templates.Accounts, xtemplates.Accounts...)
+				templates.Transactions = append(templates.Transactions, xtemplates.Transactions...)
+				continue
+			}
+
+			if se.Name.Local == "schedxaction" {
+				var xsx xmlScheduledTransaction
+				decoder.DecodeElement(&xsx, &se)
+				xscheduled = append(xscheduled, xsx)
+				continue
+			}
+
+			if se.Name.Local == "budget" {
+				var xbg xmlBudget
+				decoder.DecodeElement(&xbg, &se)
+				xbudgets = append(xbudgets, xbg)
+				continue
 			}
 
 		}
@@ -169,19 +506,195 @@ func Load(r io.Reader) (*Account, error) {
 		log.Printf("Read %d transactions when %d were expected", read.trns, expected.trns)
 	}
 
+	// keep transactions and splits of each account sorted by date
+	sort.SliceStable(trns, func(i, j int) bool { return trns[i].DatePosted < trns[j].DatePosted })
+	sort.SliceStable(unbalanced, func(i, j int) bool { return unbalanced[i].DatePosted < unbalanced[j].DatePosted })
+	for _, act := range actsIndex {
+		splits := act.Splits
+		sort.SliceStable(splits, func(i, j int) bool {
+			return splits[i].Transaction.DatePosted < splits[j].Transaction.DatePosted
+		})
+	}
+	for _, lot := range lotsIndex {
+		splits := lot.Splits
+		sort.SliceStable(splits, func(i, j int) bool {
+			return splits[i].Transaction.DatePosted < splits[j].Transaction.DatePosted
+		})
+	}
+
+	scheduled := make([]*ScheduledTransaction, 0, len(xscheduled))
+	for _, xsx := range xscheduled {
+		scheduled = append(scheduled, newScheduledTransaction(xsx, templates.Transactions, actsIndex, commodity))
+	}
+
+	budgets := make(Budgets, 0, len(xbudgets))
+	if root != nil {
+		_, natural := bookSlots.Get("features/" + FeatureNaturalBudgetSigns)
+		for _, xbg := range xbudgets {
+			budgets = append(budgets, newBudget(xbg, root, natural))
+		}
+	}
+
 	t2 := time.Now()
 	duration := t2.Sub(t1)
 	log.Printf("Gnucash data loaded in %s (%d accounts, %d transactions)", duration, read.acts, read.trns)
 
 	if root == nil {
-		return root, errors.New("Unable to parse XML file")
+		return nil, errors.New("Unable to parse XML file")
 	}
-	return root, nil
+	book := Book{
+		Root:         root,
+		Commodities:  cmdties,
+		Prices:       NewPriceDB(prices),
+		Transactions: trns,
+		Unbalanced:   unbalanced,
+		Scheduled:    scheduled,
+		Budgets:      budgets,
+		Slots:        bookSlots,
+		LoadedAt:     t2,
+		byID:         actsIndex,
+	}
+	book.index()
+	return &book, nil
+}
+
+// newScheduledTransaction converts a scheduled transaction with the templates whose splits belong to its template account.
+// The real account and the amount of a template split are stored in its "sched-xaction" slot.
+func newScheduledTransaction(xsx xmlScheduledTransaction, xtemplates []xmlTransaction, actsIndex map[string]*Account, commodity func(xmlCommodity) *Commodity) *ScheduledTransaction {
+	sx := ScheduledTransaction{
+		ID:          xsx.ID,
+		Name:        xsx.Name,
+		Enabled:     xsx.Enabled == "y",
+		AutoCreate:  xsx.AutoCreate == "y",
+		Start:       strings.TrimSpace(xsx.Start),
+		End:         strings.TrimSpace(xsx.End),
+		Last:        strings.TrimSpace(xsx.Last),
+		Occurrences: xsx.NumOccur,
+		Remaining:   xsx.RemOccur,
+		Recurrences: make([]Recurrence, 0, len(xsx.Recurrences)),
+		Templates:   make([]ScheduledTemplate, 0),
+	}
+	for _, xr := range xsx.Recurrences {
+		sx.Recurrences = append(sx.Recurrences, Recurrence{
+			Period:        xr.PeriodType,
+			Multiplier:    xr.Mult,
+			Start:         strings.TrimSpace(xr.Start),
+			WeekendAdjust: xr.WeekendAdjust,
+		})
+	}
+
+	for _, xtrn := range xtemplates {
+		if len(xtrn.Splits) == 0 || xtrn.Splits[0].Account != xsx.TemplateAccount {
+			continue
+		}
+		t := ScheduledTemplate{
+			Description: xtrn.Description,
+			Num:         xtrn.Num,
+			Currency:    commodity(xtrn.Currency),
+			Splits:      make([]ScheduledSplit, 0, len(xtrn.Splits)),
+		}
+		scu := int64(0)
+		if t.Currency != nil {
+			scu = t.Currency.Fraction
+		}
+		for _, xsplit := range xtrn.Splits {
+			slots := newSlots(xsplit.Slots)
+			ID := slots.String("sched-xaction/account")
+			ref := AccountRef{ID: ID}
+			if act := actsIndex[ID]; act != nil {
+				ref = act.Ref()
+			} else {
+				log.Printf("Account '%s' not found in index for scheduled transaction '%s'", ID, sx.Name)
+			}
+			split := ScheduledSplit{Account: ref, Memo: xsplit.Memo}
+			debit, dok := slotAmount(slots, "sched-xaction/debit-numeric")
+			credit, cok := slotAmount(slots, "sched-xaction/credit-numeric")
+			split.Amount = debit.Sub(credit).WithSCU(scu)
+			if !dok && !cok {
+				log.Printf("No amount for a split of scheduled transaction '%s'", sx.Name)
+			}
+			if formula := slots.String("sched-xaction/debit-formula"); formula != "" && !isNumber(formula) {
+				split.Formula = formula
+			}
+			if formula := slots.String("sched-xaction/credit-formula"); formula != "" && !isNumber(formula) {
+				split.Formula = "-(" + formula + ")"
+			}
+			t.Splits = append(t.Splits, split)
+		}
+		sx.Templates = append(sx.Templates, t)
+	}
+	return &sx
+}
+
+// newBudget converts a budget whose amounts are stored in slots, by account ID then by period number.
+// Accounts are listed in the Breadth-first order of the tree. Without natural signs, amounts are stored like GnuCash shows them.
+func newBudget(xbg xmlBudget, root *Account, natural bool) *Budget {
+	bg := Budget{
+		ID:          xbg.ID,
+		Name:        xbg.Name,
+		Description: xbg.Description,
+		NumPeriods:  xbg.NumPeriods,
+		Recurrence: Recurrence{
+			Period:     xbg.Recurrence.PeriodType,
+			Multiplier: xbg.Recurrence.Mult,
+			Start:      strings.TrimSpace(xbg.Recurrence.Start),
+		},
+		Accounts: make([]BudgetAccount, 0),
+	}
+	if bg.NumPeriods < 0 || bg.NumPeriods > maxPeriods {
+		log.Printf("Invalid number of periods %d for budget '%s'", bg.NumPeriods, bg.Name)
+		bg.NumPeriods = 0
+	}
+	bg.Periods = budgetPeriods(bg.Recurrence, bg.NumPeriods)
+
+	slots := newSlots(xbg.Slots)
+	root.WalkBFS(func(act *Account) bool {
+		slot, ok := slots[act.ID]
+		if !ok {
+			return false
+		}
+		frame, ok := slot.Value.(Slots)
+		if !ok {
+			return false
+		}
+		reverse := natural && (hasType(IncomeTypes, act.Type) || hasType(LiabilityTypes, act.Type) || hasType(EquityTypes, act.Type))
+		ba := BudgetAccount{Account: act.Ref(), Amounts: make([]*Amount, bg.NumPeriods), Total: Amount{SCU: act.SCU}}
+		for i := range ba.Amounts {
+			a, ok := slotAmount(frame, strconv.Itoa(i))
+			if !ok {
+				continue
+			}
+			if reverse {
+				a = a.Neg()
+			}
+			a = a.WithSCU(act.SCU)
+			ba.Amounts[i] = &a
+			ba.Total = ba.Total.Add(a)
+		}
+		bg.Accounts = append(bg.Accounts, ba)
+		return false
+	})
+	return &bg
+}
+
+// slotAmount returns the numeric slot at path
+func slotAmount(slots Slots, path string) (Amount, bool) {
+	slot, ok := slots.Get(path)
+	if !ok {
+		return Amount{}, false
+	}
+	a, ok := slot.Value.(Amount)
+	return a, ok
+}
+
+// isNumber reports whether a formula of a scheduled transaction is a plain number
+func isNumber(formula string) bool {
+	_, err := ParseAmount(formula)
+	return err == nil
 }
 
-func stringToFloat(v string) float64 {
-	i := strings.Split(v, "/")
-	n, _ := strconv.ParseFloat(i[0], 10)
-	d, _ := strconv.ParseFloat(i[1], 10)
-	return n / d
+// gncDate converts a GnuCash timestamp to a date.
+// For '2014-07-30 00:00:00 +0200', we keep only '2014-07-30'
+func gncDate(ts string) string {
+	return strings.TrimSpace(strings.Split(strings.TrimSpace(ts), " ")[0])
 }
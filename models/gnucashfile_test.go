@@ -15,8 +15,9 @@ import (
 )
 
 func TestLoadGnuCashFile(t *testing.T) {
-	data, err := LoadFromFile("testdata/empty.gnucash")
+	book, err := LoadFromFile("testdata/empty.gnucash")
 	if assert.NoError(t, err) {
+		data := book.Root
 		roots := data.FindByType("ROOT")
 		assert.Equal(t, 1, len(roots), "Problem while retrieve the account of type ROOT")
 		assert.Equal(t, "Root Account", roots[0].Name, "Problem while retrieve the Root Account")
@@ -28,10 +29,92 @@ func TestLoadGnuCashFile(t *testing.T) {
 		assert.Equal(t, 1, len(books), "Problem while retrieve the account 'Books'")
 		actBooks := books[0]
 		assert.Equal(t, "97c2d5b268164b479944e221ae0267f1", actBooks.ID, "Problem with 'Books' account ID")
-		assert.Equal(t, 1, len(actBooks.Transactions), "Problem with 'Books' account transactions")
-		trnBooks := actBooks.Transactions[0]
-		assert.Equal(t, "2019-06-10", trnBooks.Date, "Problem with 'Books' transaction date")
-		assert.Equal(t, 30.05, trnBooks.Value, "Problem with 'Books' transaction value")
+		assert.Equal(t, 1, len(actBooks.Splits), "Problem with 'Books' account splits")
+		splitBooks := actBooks.Splits[0]
+		assert.Equal(t, "b45843d63b2244d1bfe649d31e7602d7", splitBooks.ID, "Problem with 'Books' split ID")
+		assert.Equal(t, "n", splitBooks.ReconciledState, "Problem with 'Books' split reconciled state")
+		assert.Equal(t, 30.05, splitBooks.Value.Float64(), "Problem with 'Books' split value")
+		assert.Equal(t, "30.05", splitBooks.Value.String(), "Problem with 'Books' split exact value")
+		assert.Equal(t, "30.05", splitBooks.Quantity.String(), "Problem with 'Books' split quantity")
+		trnBooks := splitBooks.Transaction
+		assert.Equal(t, "1983058cd4324a2f9fdfed1e6c6b8824", trnBooks.ID, "Problem with 'Books' transaction ID")
+		assert.Equal(t, "2019-06-10", trnBooks.DatePosted, "Problem with 'Books' transaction date")
+		assert.Equal(t, "2019-06-13 19:30:15", trnBooks.DateEntered, "Problem with 'Books' transaction date entered")
+		assert.Equal(t, "CB", trnBooks.Num, "Problem with 'Books' transaction num")
+		assert.Equal(t, "The Go Programming Language", trnBooks.Description, "Problem with 'Books' transaction description")
+		assert.Equal(t, 2, len(trnBooks.Splits), "Problem with 'Books' transaction splits")
+
+		assert.Equal(t, 4, len(book.Transactions), "Problem with the number of transactions")
+		assert.Equal(t, trnBooks, book.Transactions.FindByID(trnBooks.ID), "Problem while retrieve transaction by ID")
+
+		assert.Equal(t, 1, len(book.Commodities), "Problem with the number of commodities")
+		eur := book.Commodities.Find("CURRENCY", "EUR")
+		if assert.NotNil(t, eur, "Problem while retrieve commodity EUR") {
+			assert.Equal(t, "currency", eur.QuoteSource, "Problem with EUR quote source")
+			assert.Equal(t, eur, actBooks.Commodity, "Problem with 'Books' account commodity")
+		}
+	}
+}
+
+func TestLoadPriceDB(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 3, len(book.Commodities), "Problem with the number of commodities")
+		aapl := book.Commodities.Find("NASDAQ", "AAPL")
+		if assert.NotNil(t, aapl, "Problem while retrieve commodity AAPL") {
+			assert.Equal(t, "Apple Inc.", aapl.Name, "Problem with AAPL name")
+			assert.Equal(t, "US0378331005", aapl.XCode, "Problem with AAPL xcode")
+			assert.Equal(t, int64(1), aapl.Fraction, "Problem with AAPL fraction")
+		}
+		eur := book.Commodities.Find("CURRENCY", "EUR")
+		usd := book.Commodities.Find("CURRENCY", "USD")
+		assert.Equal(t, int64(100), usd.Fraction, "Problem with USD fraction")
+
+		assert.Equal(t, 4, len(book.Prices.Prices()), "Problem with the number of prices")
+		p := book.Prices.Latest(aapl, usd, "2019-02-15")
+		if assert.NotNil(t, p, "Problem while retrieve AAPL price") {
+			assert.Equal(t, "150.00", p.Value.String(), "Problem with AAPL price value")
+			assert.Equal(t, "user:xfer-dialog", p.Source, "Problem with AAPL price source")
+		}
+		a, ok := book.Prices.Convert(NewAmount(5, 1), aapl, eur, "2019-03-01")
+		assert.True(t, ok, "Problem while converting AAPL to EUR")
+		assert.Equal(t, "752.21", a.String(), "Problem with AAPL converted to EUR")
+	}
+}
+
+var quantityBalanceTests = []struct {
+	account  string
+	options  BalanceOptions
+	expected string
+}{
+	{"Assets:Broker:AAPL", BalanceOptions{To: "2019-03-31", Quantity: true}, "3"},
+	{"Assets:Broker:AAPL", BalanceOptions{To: "2019-03-31"}, "450.00"},
+	{"Assets:US Bank", BalanceOptions{To: "2019-03-31", Quantity: true}, "710.00"},
+	{"Assets:US Bank", BalanceOptions{To: "2019-01-31", Quantity: true}, "1120.00"},
+	{"Assets:US Bank", BalanceOptions{To: "2019-01-31"}, "1000.00"},
+}
+
+func TestLoadSplitQuantity(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if assert.NoError(t, err) {
+		usd := book.Commodities.Find("CURRENCY", "USD")
+		broker := book.Root.FindByName("Broker")[0]
+		aapl := broker.FindByName("AAPL")[0]
+		bank := book.Root.FindByName("US Bank")[0]
+		acts := map[string]*Account{"Assets:Broker:AAPL": aapl, "Assets:US Bank": bank}
+
+		for _, tt := range quantityBalanceTests {
+			b := acts[tt.account].Balance(tt.options)
+			assert.Equal(t, tt.expected, b.Amount.String(), "Balance of %s with %+v is incorrect", tt.account, tt.options)
+		}
+
+		// with a currency, the quantities held in the commodity of the account are converted
+		b := bank.Balance(BalanceOptions{To: "2019-03-31", Currency: usd, Prices: book.Prices})
+		assert.Equal(t, "710.00", b.Amount.String(), "Balance converted to USD is incorrect")
+		b = bank.Balance(BalanceOptions{To: "2019-03-31", Quantity: true, Currency: usd, Prices: book.Prices})
+		assert.Equal(t, "710.00", b.Amount.String(), "Balance by quantity converted to USD is incorrect")
+		b = broker.Balance(BalanceOptions{To: "2019-03-31", Quantity: true, Recursive: true, Currency: usd, Prices: book.Prices})
+		assert.Equal(t, "510.00", b.Amount.String(), "Balance of shares converted to USD is incorrect")
 	}
 }
 
@@ -63,3 +146,22 @@ func TestLoadNotExistingFile(t *testing.T) {
 	_, err := LoadFromFile("i_do_not_exist")
 	assert.Error(t, err)
 }
+
+func TestLoadAccountMetadata(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	checking := book.AccountByPath("Assets:Checking Account")
+	assert.Equal(t, "1010", checking.Code, "Problem with account code")
+	assert.Equal(t, "Checking Account", checking.Description, "Problem with account description")
+	assert.Equal(t, "Main account", checking.Notes, "Problem with account notes")
+	assert.False(t, checking.Placeholder, "Problem with account placeholder flag")
+	assert.False(t, checking.Hidden, "Problem with account hidden flag")
+
+	assert.True(t, book.AccountByPath("Assets").Placeholder, "Problem with account placeholder flag")
+	assert.True(t, book.AccountByPath("Expenses:Home").Hidden, "Problem with account hidden flag")
+	assert.True(t, book.AccountByPath("Income:Capital Gains").TaxRelated, "Problem with account tax-related flag")
+	assert.False(t, book.AccountByPath("Income:Salary").TaxRelated, "Problem with account tax-related flag")
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Periods an income statement can be compared to
+const (
+	PreviousPeriod = "previous-period" // period of the same length ending the day before From
+	PreviousYear   = "previous-year"   // same period one year before
+)
+
+// IncomeStatement is the profit and loss of a period.
+// Income is shown as positive numbers like in GnuCash, NetIncome is Income - Expenses.
+type IncomeStatement struct {
+	From             string        `json:"from,omitempty"`
+	To               string        `json:"to"`
+	Currency         string        `json:"currency,omitempty"`
+	Income           ReportSection `json:"income"`
+	Expenses         ReportSection `json:"expenses"`
+	NetIncome        Amount        `json:"net_income"`
+	CompareFrom      string        `json:"compare_from,omitempty"`
+	CompareTo        string        `json:"compare_to,omitempty"`
+	CompareNetIncome *Amount       `json:"compare_net_income,omitempty"`
+	Unpriced         []string      `json:"unpriced,omitempty"`
+}
+
+// IncomeStatement returns the income statement of the book between opts.From and opts.To.
+// With compare, each amount is given with the amount of the previous period or year.
+func (b *Book) IncomeStatement(opts BalanceOptions, compare string) (IncomeStatement, error) {
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+	opts = BalanceOptions{From: opts.From, To: opts.To, Currency: opts.Currency, Prices: opts.Prices}
+
+	is := b.incomeStatement(opts)
+	if compare == "" {
+		return is, nil
+	}
+
+	from, to, err := comparePeriod(opts.From, opts.To, compare)
+	if err != nil {
+		return is, err
+	}
+	opts.From, opts.To = from, to
+	previous := b.incomeStatement(opts)
+	is.CompareFrom, is.CompareTo = previous.From, previous.To
+	is.CompareNetIncome = &previous.NetIncome
+	is.Income.compareWith(previous.Income)
+	is.Expenses.compareWith(previous.Expenses)
+	for _, c := range previous.Unpriced {
+		is.Unpriced = appendOnce(is.Unpriced, c)
+	}
+	sort.Strings(is.Unpriced)
+	return is, nil
+}
+
+func (b *Book) incomeStatement(opts BalanceOptions) IncomeStatement {
+	is := IncomeStatement{From: opts.From, To: opts.To}
+	var unpriced [2][]string
+	is.Income, unpriced[0] = newReportSection(b.Root, IncomeTypes, opts, true)
+	is.Expenses, unpriced[1] = newReportSection(b.Root, ExpenseTypes, opts, false)
+	is.NetIncome = is.Income.Total.Sub(is.Expenses.Total)
+	for _, l := range unpriced {
+		for _, c := range l {
+			is.Unpriced = appendOnce(is.Unpriced, c)
+		}
+	}
+	sort.Strings(is.Unpriced)
+	if opts.Currency != nil {
+		is.Currency = opts.Currency.String()
+	}
+	return is
+}
+
+// comparePeriod returns the period to compare to the period from - to
+func comparePeriod(from string, to string, compare string) (string, string, error) {
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return "", "", err
+	}
+	var start time.Time
+	if from != "" {
+		if start, err = time.Parse("2006-01-02", from); err != nil {
+			return "", "", err
+		}
+		if end.Before(start) {
+			return "", "", errors.New("end of period is before its start")
+		}
+	}
+
+	switch compare {
+	case PreviousPeriod:
+		if from == "" {
+			return "", "", fmt.Errorf("comparison to '%s' requires the start of the period", compare)
+		}
+		if start.Day() == 1 && isEndOfMonth(end) {
+			// whole months are compared to the same number of calendar months
+			months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month()) + 1
+			end = start.AddDate(0, 0, -1)
+			start = addMonths(start, -months, 1)
+			break
+		}
+		days := int(end.Sub(start).Hours()/24) + 1
+		end = start.AddDate(0, 0, -1)
+		start = end.AddDate(0, 0, 1-days)
+	case PreviousYear:
+		day := end.Day()
+		if isEndOfMonth(end) {
+			day = 31 // the end of february stays the end of february
+		}
+		end = addMonths(end, -12, day)
+		if from == "" {
+			return "", end.Format("2006-01-02"), nil
+		}
+		start = addMonths(start, -12, start.Day())
+	default:
+		return "", "", fmt.Errorf("unknown comparison '%s'", compare)
+	}
+	return start.Format("2006-01-02"), end.Format("2006-01-02"), nil
+}
+
+// isEndOfMonth tells if d is the last day of its month
+func isEndOfMonth(d time.Time) bool {
+	return d.AddDate(0, 0, 1).Day() == 1
+}
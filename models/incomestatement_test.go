@@ -0,0 +1,91 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var comparePeriodTests = []struct {
+	from    string
+	to      string
+	compare string
+	expFrom string
+	expTo   string
+}{
+	{"2019-02-01", "2019-02-28", PreviousPeriod, "2019-01-01", "2019-01-31"},
+	{"2019-03-01", "2019-03-31", PreviousPeriod, "2019-02-01", "2019-02-28"},
+	{"2019-01-01", "2019-03-31", PreviousPeriod, "2018-10-01", "2018-12-31"},
+	{"2019-02-10", "2019-02-19", PreviousPeriod, "2019-01-31", "2019-02-09"},
+	{"2019-06-15", "2019-06-15", PreviousPeriod, "2019-06-14", "2019-06-14"},
+	{"2019-01-01", "2019-12-31", PreviousYear, "2018-01-01", "2018-12-31"},
+	{"2020-02-01", "2020-02-29", PreviousYear, "2019-02-01", "2019-02-28"},
+	{"2020-02-29", "2020-02-29", PreviousYear, "2019-02-28", "2019-02-28"},
+	{"2021-02-01", "2021-02-28", PreviousYear, "2020-02-01", "2020-02-29"},
+	{"", "2019-12-31", PreviousYear, "", "2018-12-31"},
+}
+
+func TestComparePeriod(t *testing.T) {
+	for _, tt := range comparePeriodTests {
+		from, to, err := comparePeriod(tt.from, tt.to, tt.compare)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tt.expFrom, from, "Problem with start of %s of %s - %s", tt.compare, tt.from, tt.to)
+			assert.Equal(t, tt.expTo, to, "Problem with end of %s of %s - %s", tt.compare, tt.from, tt.to)
+		}
+	}
+
+	_, _, err := comparePeriod("", "2019-12-31", PreviousPeriod)
+	assert.Error(t, err, "Problem with previous period without start")
+	_, _, err = comparePeriod("2019-12-31", "2019-01-01", PreviousPeriod)
+	assert.Error(t, err, "Problem with a period ending before its start")
+	_, _, err = comparePeriod("2019-01-01", "2019-12-31", "previous-decade")
+	assert.Error(t, err, "Problem with an unknown comparison")
+}
+
+func TestIncomeStatement(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	is, err := book.IncomeStatement(BalanceOptions{From: "2019-02-01", To: "2019-02-28"}, "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2000.00", is.Income.Total.String(), "Problem with total of income")
+		assert.Equal(t, "300.00", is.Expenses.Total.String(), "Problem with total of expenses")
+		assert.Equal(t, "1700.00", is.NetIncome.String(), "Problem with net income")
+		assert.Nil(t, is.CompareNetIncome, "Problem with an income statement without comparison")
+		if assert.Equal(t, 1, len(is.Expenses.Accounts), "Problem with the number of top level expenses") {
+			assert.Equal(t, 3, len(is.Expenses.Accounts[0].Children), "Problem with sub-accounts of expenses")
+		}
+	}
+
+	is, err = book.IncomeStatement(BalanceOptions{From: "2019-02-01", To: "2019-02-28"}, PreviousPeriod)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2019-01-01", is.CompareFrom, "Problem with start of the compared period")
+		assert.Equal(t, "1700.00", is.NetIncome.String(), "Problem with net income")
+		if assert.NotNil(t, is.CompareNetIncome) {
+			assert.Equal(t, "1884.80", is.CompareNetIncome.String(), "Problem with net income of the previous period")
+		}
+		if assert.NotNil(t, is.Expenses.CompareTotal) {
+			assert.Equal(t, "115.20", is.Expenses.CompareTotal.String(), "Problem with expenses of the previous period")
+		}
+		groceries := is.Expenses.Accounts[0].Children[0]
+		assert.Equal(t, "Groceries", groceries.Account.Name, "Problem with expense account")
+		if assert.NotNil(t, groceries.CompareTotal) {
+			assert.True(t, groceries.Total.IsZero(), "Problem with total of %s", groceries.Account.Name)
+			assert.Equal(t, "55.20", groceries.CompareTotal.String(), "Problem with total of %s for the previous period", groceries.Account.Name)
+		}
+	}
+
+	is, err = book.IncomeStatement(BalanceOptions{From: "2019-01-01", To: "2019-12-31"}, PreviousYear)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "3624.80", is.NetIncome.String(), "Problem with net income")
+		assert.True(t, is.CompareNetIncome.IsZero(), "Problem with net income of the previous year")
+	}
+
+	_, err = book.IncomeStatement(BalanceOptions{To: "2019-12-31"}, PreviousPeriod)
+	assert.Error(t, err, "Problem with previous period without start")
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+)
+
+// ledger keeps splits sorted by date with the cumulative sums of their values and quantities,
+// so that the sum of the splits between two dates is found with a binary search.
+type ledger struct {
+	splits     []*Split
+	values     []Amount // values[i] is the sum of the values of splits[0..i]
+	quantities []Amount
+	currency   *Commodity // currency of all the values, nil if there are several
+	mixed      bool       // values are expressed in several currencies
+	commodity  *Commodity // commodity of the accounts of all the quantities, nil if there are several
+	mixedQty   bool       // quantities are expressed in several commodities
+}
+
+func newLedger(splits []*Split) *ledger {
+	l := &ledger{
+		splits:     make([]*Split, len(splits)),
+		values:     make([]Amount, len(splits)),
+		quantities: make([]Amount, len(splits)),
+	}
+	copy(l.splits, splits)
+	sort.SliceStable(l.splits, func(i, j int) bool {
+		return l.splits[i].Transaction.DatePosted < l.splits[j].Transaction.DatePosted
+	})
+
+	var value, quantity Amount
+	for i, s := range l.splits {
+		value = value.Add(s.Value)
+		quantity = quantity.Add(s.Quantity)
+		l.values[i] = value
+		l.quantities[i] = quantity
+
+		if c := s.Transaction.Currency; c != l.currency {
+			if i > 0 {
+				l.mixed = true
+			}
+			l.currency = c
+		}
+	}
+	if l.mixed {
+		l.currency = nil
+	}
+	return l
+}
+
+// mixedIn tells if the values, or quantities, of the ledger are expressed in several commodities
+func (l *ledger) mixedIn(quantity bool) bool {
+	if quantity {
+		return l.mixedQty
+	}
+	return l.mixed
+}
+
+// commodityOf returns the commodity of the values, or quantities, of the ledger, nil if there are several
+func (l *ledger) commodityOf(quantity bool) *Commodity {
+	if !quantity && l.currency != nil {
+		return l.currency
+	}
+	if l.mixedIn(quantity) {
+		return nil
+	}
+	return l.commodity
+}
+
+// sum returns the sum of the values, or quantities, of the splits posted between from and to included
+func (l *ledger) sum(from string, to string, quantity bool) Amount {
+	i := sort.Search(len(l.splits), func(i int) bool { return l.splits[i].Transaction.DatePosted >= from })
+	j := sort.Search(len(l.splits), func(j int) bool { return l.splits[j].Transaction.DatePosted > to })
+	if j <= i {
+		return Amount{}
+	}
+
+	cum := l.values
+	if quantity {
+		cum = l.quantities
+	}
+	if i == 0 {
+		return cum[j-1]
+	}
+	return cum[j-1].Sub(cum[i-1])
+}
+
+// ledger returns the ledger of the splits of the account, built on first use
+func (a *Account) ledger() *ledger {
+	a.ledgerOnce.Do(func() {
+		a.ownLedger = newLedger(a.Splits)
+		a.ownLedger.commodity = a.Commodity
+	})
+	return a.ownLedger
+}
+
+// subtreeLedger returns the ledger of the splits of the account and of all its sub-accounts, built on first use
+func (a *Account) subtreeLedger() *ledger {
+	a.subtreeOnce.Do(func() {
+		splits := make([]*Split, 0, len(a.Splits))
+		var commodity *Commodity
+		mixed := false
+		a.WalkBFS(func(act *Account) bool {
+			if len(act.Splits) > 0 {
+				if commodity != nil && act.Commodity != commodity {
+					mixed = true
+				}
+				commodity = act.Commodity
+			}
+			splits = append(splits, act.Splits...)
+			return false
+		})
+		a.subtree = newLedger(splits)
+		a.subtree.mixedQty = mixed
+		if !mixed {
+			a.subtree.commodity = commodity
+		}
+		if a.subtree.currency == nil && mixed {
+			a.subtree.mixed = true // values without currency are expressed in the commodity of their account
+		}
+	})
+	return a.subtree
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// scanBalance computes a balance by scanning all the splits of the sub-tree
+func scanBalance(a *Account, opts BalanceOptions) Amount {
+	var b Amount
+	for _, act := range a.WalkBFS(func(act *Account) bool { return act == a || opts.Recursive }) {
+		for _, s := range act.Splits {
+			t := s.Transaction
+			if opts.Type != "" && t.Num != opts.Type {
+				continue
+			}
+			if t.DatePosted >= opts.From && t.DatePosted <= opts.To {
+				b = b.Add(s.Amount(opts))
+			}
+		}
+	}
+	return b
+}
+
+func TestLedger(t *testing.T) {
+	splits := []*Split{
+		{Value: NewAmount(300, 100), Quantity: NewAmount(3, 1), Transaction: &Transaction{DatePosted: "2019-03-01"}},
+		{Value: NewAmount(100, 100), Quantity: NewAmount(1, 1), Transaction: &Transaction{DatePosted: "2019-01-01"}},
+		{Value: NewAmount(200, 100), Quantity: NewAmount(2, 1), Transaction: &Transaction{DatePosted: "2019-02-01"}},
+		{Value: NewAmount(250, 100), Quantity: NewAmount(2, 1), Transaction: &Transaction{DatePosted: "2019-02-01"}},
+	}
+	l := newLedger(splits)
+
+	assert.Equal(t, "8.50", l.sum("", "2019-12-31", false).String(), "Problem with sum of all splits")
+	assert.Equal(t, "4.50", l.sum("2019-02-01", "2019-02-01", false).String(), "Problem with sum of splits of a day")
+	assert.Equal(t, "7", l.sum("2019-01-15", "2019-03-01", true).String(), "Problem with sum of quantities")
+	assert.True(t, l.sum("2019-04-01", "2019-12-31", false).IsZero(), "Problem with sum after last split")
+	assert.True(t, l.sum("2019-02-02", "2019-02-28", false).IsZero(), "Problem with sum between splits")
+	assert.True(t, l.sum("2019-03-01", "2019-01-01", false).IsZero(), "Problem with sum of an empty period")
+	assert.False(t, l.mixed, "Problem with currency of ledger")
+}
+
+func TestBalanceMatchesScan(t *testing.T) {
+	book := generateBook(300, 5000)
+	acts := append(book.Root.Descendants(), book.Root)
+	dates := []string{"", "1999-12-31", "2000-01-01", "2003-06-15", "2010-02-28", "2019-12-28", "2030-01-01"}
+	types := []string{"", "0", "2"}
+
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 2000; i++ {
+		opts := BalanceOptions{
+			From:      dates[r.Intn(len(dates))],
+			To:        dates[1+r.Intn(len(dates)-1)],
+			Type:      types[r.Intn(len(types))],
+			Recursive: r.Intn(2) == 0,
+			Quantity:  r.Intn(2) == 0,
+		}
+		act := acts[r.Intn(len(acts))]
+		expected := scanBalance(act, opts)
+		got := act.Balance(opts).Amount
+		if !assert.Equal(t, 0, expected.Cmp(got), "Balance of %s with %+v is %s instead of %s", act.ID, opts, got, expected) {
+			return
+		}
+	}
+}
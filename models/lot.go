@@ -0,0 +1,267 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Lot is a set of splits of an account tracked together, like the shares bought at once
+// and the sales of these shares. Splits are sorted by date.
+type Lot struct {
+	ID      string
+	Title   string
+	Notes   string
+	Account *Account
+	Slots   Slots
+	Splits  []*Split
+}
+
+// LotSale is the sale of a part of a lot. Cost is the part of the cost of the lot sold,
+// Gain is the realized gain, Proceeds minus Cost.
+type LotSale struct {
+	Date        string `json:"date"`
+	Transaction string `json:"transaction"`
+	Description string `json:"description"`
+	Quantity    Amount `json:"quantity"`
+	Proceeds    Amount `json:"proceeds"`
+	Cost        Amount `json:"cost"`
+	Gain        Amount `json:"gain"`
+}
+
+// LotStatus is the state of a lot at a date.
+// Quantity and Cost are what remains of the lot, Closed is set once all its shares have been sold.
+type LotStatus struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title,omitempty"`
+	Notes    string     `json:"notes,omitempty"`
+	Account  AccountRef `json:"account"`
+	Opened   string     `json:"opened"`
+	Closed   string     `json:"closed,omitempty"`
+	Currency string     `json:"currency,omitempty"`
+	Bought   Amount     `json:"bought"`
+	Quantity Amount     `json:"quantity"`
+	Cost     Amount     `json:"cost"`
+	Sales    []LotSale  `json:"sales"`
+	Realized Amount     `json:"realized"`
+	Unpriced []string   `json:"unpriced,omitempty"`
+
+	currency *Commodity
+}
+
+// Status returns the state of the lot at opts.To (default today).
+// Sold shares are valued at the average cost of the lot. With opts.Currency, purchases are
+// converted at the date of their transaction and sales at the date of the sale.
+func (l *Lot) Status(opts BalanceOptions) LotStatus {
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+	st := LotStatus{ID: l.ID, Title: l.Title, Notes: l.Notes, Sales: make([]LotSale, 0)}
+	if l.Account != nil {
+		st.Account = l.Account.Ref()
+		st.Bought = Amount{SCU: l.Account.SCU}
+		st.Quantity = Amount{SCU: l.Account.SCU}
+	}
+	if opts.Currency != nil {
+		st.currency = opts.Currency
+	}
+
+	for _, s := range l.Splits {
+		t := s.Transaction
+		if t.DatePosted > opts.To {
+			break
+		}
+		if st.Opened == "" {
+			st.Opened = t.DatePosted
+		}
+		if st.currency == nil {
+			st.currency = t.Currency
+		}
+		switch s.Quantity.Sign() {
+		case 1:
+			st.Bought = st.Bought.Add(s.Quantity)
+			st.Quantity = st.Quantity.Add(s.Quantity)
+			cost, _ := convertSplitValue(s.Value, s, opts, &st.Unpriced)
+			st.Cost = st.Cost.Add(cost)
+		case -1:
+			sold := s.Quantity.Neg()
+			cost := st.Cost
+			if st.Quantity.Cmp(sold) > 0 {
+				cost = st.Cost.Mul(sold.Mul(st.Quantity.Inv())).Round(st.Cost.SCU)
+			}
+			proceeds, _ := convertSplitValue(saleProceeds(s), s, opts, &st.Unpriced)
+			sale := LotSale{
+				Date:        t.DatePosted,
+				Transaction: t.ID,
+				Description: t.Description,
+				Quantity:    sold,
+				Proceeds:    proceeds,
+				Cost:        cost,
+				Gain:        proceeds.Sub(cost),
+			}
+			st.Sales = append(st.Sales, sale)
+			st.Realized = st.Realized.Add(sale.Gain)
+			st.Quantity = st.Quantity.Add(s.Quantity)
+			st.Cost = st.Cost.Sub(cost)
+			if st.Quantity.IsZero() {
+				st.Closed = t.DatePosted
+			}
+		}
+		// splits without quantity are the gains booked by GnuCash, computed here from the sales
+	}
+	if st.currency != nil {
+		st.Currency = st.currency.String()
+	}
+	sort.Strings(st.Unpriced)
+	return st
+}
+
+// saleProceeds returns the money received for the shares sold by s: the value of the other splits
+// of its transaction which are neither investments nor income or expenses, shared between the
+// sales of the transaction. Without such splits, it is the value of s.
+func saleProceeds(s *Split) Amount {
+	var received, sold Amount
+	for _, o := range s.Transaction.Splits {
+		if o.Account == nil {
+			continue
+		}
+		switch {
+		case hasType(InvestmentTypes, o.Account.Type):
+			if o.Quantity.Sign() < 0 {
+				sold = sold.Add(o.Value)
+			}
+		case hasType(IncomeTypes, o.Account.Type), hasType(ExpenseTypes, o.Account.Type):
+		default:
+			received = received.Add(o.Value)
+		}
+	}
+	if !hasType(InvestmentTypes, s.Account.Type) || received.IsZero() || sold.IsZero() {
+		return s.Value.Neg()
+	}
+	return received.Mul(s.Value.Mul(sold.Inv())).Round(s.Value.SCU)
+}
+
+// convertSplitValue converts v, a value in the currency of the transaction of s, to opts.Currency at the date
+// of the transaction. When no price is found, the currency is added to unpriced and false is returned.
+func convertSplitValue(v Amount, s *Split, opts BalanceOptions, unpriced *[]string) (Amount, bool) {
+	t := s.Transaction
+	if opts.Currency == nil || t.Currency == nil {
+		return v, true
+	}
+	converted, ok := opts.Prices.Convert(v, t.Currency, opts.Currency, t.DatePosted)
+	if !ok {
+		*unpriced = appendOnce(*unpriced, t.Currency.String())
+	}
+	return converted, ok
+}
+
+// AllLots returns the lots of the account followed, for investment accounts, by the splits not assigned to a lot
+// tracked as a single lot without ID
+func (a *Account) AllLots() []*Lot {
+	lots := a.Lots
+	if !hasType(InvestmentTypes, a.Type) {
+		return lots
+	}
+	if l := a.unassignedLot(); l != nil {
+		lots = append(lots[:len(lots):len(lots)], l)
+	}
+	return lots
+}
+
+// unassignedLot returns the splits of an account which are not in a lot as a single lot, or nil if there is none
+func (a *Account) unassignedLot() *Lot {
+	l := &Lot{Account: a}
+	for _, s := range a.Splits {
+		if s.Lot == nil {
+			l.Splits = append(l.Splits, s)
+		}
+	}
+	if len(l.Splits) == 0 {
+		return nil
+	}
+	return l
+}
+
+// CapitalGain is a sale of shares of a lot
+type CapitalGain struct {
+	Account AccountRef `json:"account"`
+	Lot     string     `json:"lot,omitempty"` // ID, empty for splits not assigned to a lot
+	Title   string     `json:"title,omitempty"`
+	Opened  string     `json:"opened"`
+	LotSale
+}
+
+// UnrealizedGain is the gain on the shares of a lot still held, valued with the price database
+type UnrealizedGain struct {
+	Account  AccountRef `json:"account"`
+	Lot      string     `json:"lot,omitempty"`
+	Title    string     `json:"title,omitempty"`
+	Opened   string     `json:"opened"`
+	Quantity Amount     `json:"quantity"`
+	Cost     Amount     `json:"cost"`
+	Value    Amount     `json:"value"`
+	Gain     Amount     `json:"gain"`
+}
+
+// CapitalGains lists the gains realized between From and To and the unrealized gains at To
+type CapitalGains struct {
+	From            string           `json:"from,omitempty"`
+	To              string           `json:"to"`
+	Currency        string           `json:"currency,omitempty"`
+	Realized        []CapitalGain    `json:"realized"`
+	Unrealized      []UnrealizedGain `json:"unrealized"`
+	TotalRealized   Amount           `json:"total_realized"`
+	TotalUnrealized Amount           `json:"total_unrealized"`
+	Unpriced        []string         `json:"unpriced,omitempty"`
+}
+
+// CapitalGains returns the gains of the STOCK and MUTUAL accounts between opts.From and opts.To (default today).
+// Splits not assigned to a lot are tracked as one lot by account. Shares still held are valued
+// at the latest price at opts.To, in opts.Currency or in the currency of their lot.
+func (b *Book) CapitalGains(opts BalanceOptions) CapitalGains {
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+	cg := CapitalGains{From: opts.From, To: opts.To, Realized: make([]CapitalGain, 0), Unrealized: make([]UnrealizedGain, 0)}
+	if opts.Currency != nil {
+		cg.Currency = opts.Currency.String()
+	}
+
+	for _, act := range b.Accounts() {
+		if !hasType(InvestmentTypes, act.Type) {
+			continue
+		}
+		for _, l := range act.AllLots() {
+			st := l.Status(opts)
+			for _, c := range st.Unpriced {
+				cg.Unpriced = appendOnce(cg.Unpriced, c)
+			}
+			for _, sale := range st.Sales {
+				if sale.Date < opts.From {
+					continue
+				}
+				cg.Realized = append(cg.Realized, CapitalGain{Account: st.Account, Lot: st.ID, Title: st.Title, Opened: st.Opened, LotSale: sale})
+				cg.TotalRealized = cg.TotalRealized.Add(sale.Gain)
+			}
+			if st.Quantity.Sign() <= 0 {
+				continue
+			}
+			if st.currency == nil || act.Commodity == nil {
+				continue
+			}
+			value, ok := b.Prices.Convert(st.Quantity, act.Commodity, st.currency, opts.To)
+			if !ok {
+				cg.Unpriced = appendOnce(cg.Unpriced, act.Commodity.String())
+				continue
+			}
+			ug := UnrealizedGain{Account: st.Account, Lot: st.ID, Title: st.Title, Opened: st.Opened, Quantity: st.Quantity, Cost: st.Cost, Value: value, Gain: value.Sub(st.Cost)}
+			cg.Unrealized = append(cg.Unrealized, ug)
+			cg.TotalUnrealized = cg.TotalUnrealized.Add(ug.Gain)
+		}
+	}
+	sort.SliceStable(cg.Realized, func(i, j int) bool { return cg.Realized[i].Date < cg.Realized[j].Date })
+	sort.Strings(cg.Unpriced)
+	return cg
+}
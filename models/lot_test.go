@@ -0,0 +1,132 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadLots(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	aapl := book.AccountByPath("Assets:Broker:AAPL")
+	if !assert.Equal(t, 1, len(aapl.Lots), "Problem with the number of lots of %s", aapl.Name) {
+		return
+	}
+	lot := aapl.Lots[0]
+	assert.Equal(t, "Lot 1", lot.Title, "Problem with title of lot")
+	assert.Equal(t, aapl, lot.Account, "Problem with account of lot")
+	if assert.Equal(t, 2, len(lot.Splits), "Problem with splits of lot") {
+		assert.Equal(t, lot, lot.Splits[0].Lot, "Problem with lot of split")
+		assert.Equal(t, "2019-02-01", lot.Splits[0].Transaction.DatePosted, "Problem with order of splits of lot")
+	}
+	assert.Empty(t, book.AccountByPath("Assets:US Bank").Lots, "Problem with account without lots")
+}
+
+func TestLotStatus(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lot := book.AccountByPath("Assets:Broker:AAPL").Lots[0]
+
+	st := lot.Status(BalanceOptions{To: "2019-02-15"})
+	assert.Equal(t, "2019-02-01", st.Opened, "Problem with opening date of lot")
+	assert.Equal(t, "5", st.Quantity.String(), "Problem with quantity of lot before the sale")
+	assert.Equal(t, "750.00", st.Cost.String(), "Problem with cost of lot before the sale")
+	assert.Empty(t, st.Sales, "Problem with sales of lot before the sale")
+
+	st = lot.Status(BalanceOptions{To: "2019-03-31"})
+	assert.Equal(t, "CURRENCY:USD", st.Currency, "Problem with currency of lot")
+	assert.Equal(t, "5", st.Bought.String(), "Problem with quantity bought")
+	assert.Equal(t, "3", st.Quantity.String(), "Problem with remaining quantity of lot")
+	assert.Equal(t, "450.00", st.Cost.String(), "Problem with remaining cost of lot")
+	assert.Empty(t, st.Closed, "Problem with open lot")
+	if assert.Equal(t, 1, len(st.Sales), "Problem with sales of lot") {
+		sale := st.Sales[0]
+		assert.Equal(t, "340.00", sale.Proceeds.String(), "Proceeds of a sale recorded at cost should come from the money received")
+		assert.Equal(t, "300.00", sale.Cost.String(), "Problem with cost of shares sold")
+		assert.Equal(t, "40.00", sale.Gain.String(), "Problem with gain of sale")
+	}
+	assert.Equal(t, "40.00", st.Realized.String(), "Problem with realized gain of lot")
+
+	eur := book.Commodities.Find(CurrencySpace, "EUR")
+	st = lot.Status(BalanceOptions{To: "2019-03-31", Currency: eur, Prices: book.Prices})
+	assert.Equal(t, "401.78", st.Cost.String(), "Problem with converted cost of lot")
+	assert.Equal(t, "33.02", st.Realized.String(), "Problem with converted realized gain of lot")
+	assert.Empty(t, st.Unpriced, "Problem with unpriced commodities")
+}
+
+// testBroker returns a book with a STOCK account whose splits are not assigned to lots,
+// the first sale recorded like GnuCash does with a separate transaction for the gain
+func testBroker() *Book {
+	usd := &Commodity{Space: CurrencySpace, ID: "USD", Fraction: 100}
+	xyz := &Commodity{Space: "NYSE", ID: "XYZ", Fraction: 1}
+	root := &Account{ID: "root", Type: "ROOT"}
+	broker := &Account{ID: "broker", Name: "XYZ", Type: "STOCK", Commodity: xyz, SCU: 1, Parent: root}
+	bank := &Account{ID: "bank", Name: "Bank", Type: "BANK", Commodity: usd, SCU: 100, Parent: root}
+	gains := &Account{ID: "gains", Name: "Gains", Type: "INCOME", Commodity: usd, SCU: 100, Parent: root}
+	fees := &Account{ID: "fees", Name: "Fees", Type: "EXPENSE", Commodity: usd, SCU: 100, Parent: root}
+	root.Children = []*Account{broker, bank, gains, fees}
+
+	transaction := func(id string, date string, splits ...*Split) {
+		t := &Transaction{ID: id, DatePosted: date, Currency: usd, Splits: splits}
+		for _, s := range splits {
+			s.Transaction = t
+			s.Account.Splits = append(s.Account.Splits, s)
+		}
+	}
+	split := func(act *Account, value int64, quantity int64) *Split {
+		return &Split{Account: act, Value: NewAmount(value, 100), Quantity: NewAmount(quantity, 1)}
+	}
+	transaction("buy", "2019-01-01", split(bank, -100000, -1000), split(broker, 100000, 10))
+	transaction("sell1", "2019-02-01", split(broker, -60000, -5), split(bank, 60000, 600))
+	transaction("gain1", "2019-02-01", split(broker, 10000, 0), split(gains, -10000, -100))
+	transaction("sell2", "2019-03-01", split(broker, -40000, -5), split(bank, 39000, 390), split(fees, 1000, 10))
+
+	return &Book{Root: root, Commodities: Commodities{usd, xyz}}
+}
+
+func TestCapitalGains(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cg := book.CapitalGains(BalanceOptions{From: "2019-01-01", To: "2019-03-31"})
+	if assert.Equal(t, 1, len(cg.Realized), "Problem with the number of realized gains") {
+		assert.Equal(t, "AAPL", cg.Realized[0].Account.Name, "Problem with account of realized gain")
+		assert.Equal(t, "Lot 1", cg.Realized[0].Title, "Problem with lot of realized gain")
+		assert.Equal(t, "40.00", cg.Realized[0].Gain.String(), "Problem with realized gain")
+	}
+	if assert.Equal(t, 1, len(cg.Unrealized), "Problem with the number of unrealized gains") {
+		assert.Equal(t, "510.00", cg.Unrealized[0].Value.String(), "Problem with value of shares held")
+		assert.Equal(t, "60.00", cg.Unrealized[0].Gain.String(), "Problem with unrealized gain")
+	}
+	assert.Equal(t, "40.00", cg.TotalRealized.String(), "Problem with total realized gains")
+	assert.Equal(t, "60.00", cg.TotalUnrealized.String(), "Problem with total unrealized gains")
+
+	cg = book.CapitalGains(BalanceOptions{From: "2019-03-02", To: "2019-03-31"})
+	assert.Empty(t, cg.Realized, "Sales before the period should be ignored")
+
+	eur := book.Commodities.Find(CurrencySpace, "EUR")
+	cg = book.CapitalGains(BalanceOptions{To: "2019-03-31", Currency: eur, Prices: book.Prices})
+	assert.Equal(t, "33.02", cg.TotalRealized.String(), "Problem with converted realized gains")
+	assert.Equal(t, "49.55", cg.TotalUnrealized.String(), "Problem with converted unrealized gains")
+
+	cg = testBroker().CapitalGains(BalanceOptions{To: "2019-03-31"})
+	if assert.Equal(t, 2, len(cg.Realized), "Problem with gains of splits not assigned to a lot") {
+		assert.Empty(t, cg.Realized[0].Lot, "Problem with lot of splits not assigned to a lot")
+		assert.Equal(t, "600.00", cg.Realized[0].Proceeds.String(), "Problem with proceeds of a sale at market value")
+		assert.Equal(t, "100.00", cg.Realized[0].Gain.String(), "Problem with gain of a sale at market value")
+		assert.Equal(t, "390.00", cg.Realized[1].Proceeds.String(), "Proceeds should be net of fees")
+		assert.Equal(t, "-110.00", cg.Realized[1].Gain.String(), "Problem with loss")
+	}
+	assert.Empty(t, cg.Unrealized, "Problem with unrealized gains of a closed position")
+	assert.Equal(t, "-10.00", cg.TotalRealized.String(), "Problem with total realized gains")
+	assert.Equal(t, []string(nil), cg.Unpriced, "Problem with unpriced commodities")
+}
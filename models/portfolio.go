@@ -0,0 +1,240 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Holding is an investment account of a portfolio valued at a date.
+// Share is the part of the value of the portfolio in percentage and Return the money-weighted
+// annual return (XIRR) in percentage over the window of the portfolio, nil when it can not be computed.
+type Holding struct {
+	Account   AccountRef `json:"account"`
+	Type      string     `json:"type"`
+	Commodity *Commodity `json:"commodity,omitempty"`
+	Quantity  Amount     `json:"quantity"`
+	Price     *Amount    `json:"price,omitempty"`
+	Value     Amount     `json:"value"`
+	Cost      Amount     `json:"cost"`
+	Gain      Amount     `json:"gain"`
+	Share     float64    `json:"share"`
+	Return    *float64   `json:"return,omitempty"`
+}
+
+// Portfolio lists the holdings of the STOCK and MUTUAL accounts at Date, in Currency.
+// Return is computed from From, or from the first split of each holding.
+type Portfolio struct {
+	From     string    `json:"from,omitempty"`
+	Date     string    `json:"date"`
+	Currency string    `json:"currency"`
+	Holdings []Holding `json:"holdings"`
+	Value    Amount    `json:"value"`
+	Cost     Amount    `json:"cost"`
+	Gain     Amount    `json:"gain"`
+	Return   *float64  `json:"return,omitempty"`
+	Unpriced []string  `json:"unpriced,omitempty"`
+}
+
+// cashFlow is an amount invested (negative) or received (positive) at a date
+type cashFlow struct {
+	date   time.Time
+	amount float64
+}
+
+// Portfolio values the investment accounts of the book at opts.To (default today) with the latest prices,
+// in opts.Currency or by default in the commodity of the root account.
+// The return of a holding takes into account its value at opts.From, the purchases and the sales
+// until opts.To and its value at opts.To. Dividends paid to other accounts are not included.
+func (b *Book) Portfolio(opts BalanceOptions) Portfolio {
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+	if opts.Currency == nil && b.Root != nil {
+		opts.Currency = b.Root.Commodity
+	}
+	if opts.Prices == nil {
+		opts.Prices = b.Prices
+	}
+	pf := Portfolio{From: opts.From, Date: opts.To, Holdings: make([]Holding, 0)}
+	if opts.Currency == nil {
+		return pf
+	}
+	pf.Currency = opts.Currency.String()
+	scu := opts.Currency.Fraction
+	pf.Value, pf.Cost, pf.Gain = Amount{SCU: scu}, Amount{SCU: scu}, Amount{SCU: scu}
+
+	flows := make([]cashFlow, 0)
+	complete := true // the return of the portfolio is unknown when the return of a holding is
+	for _, act := range b.Accounts() {
+		if !hasType(InvestmentTypes, act.Type) || act.Commodity == nil {
+			continue
+		}
+		h, hflows, ok := b.holding(act, opts, &pf.Unpriced)
+		if !ok {
+			continue
+		}
+		pf.Holdings = append(pf.Holdings, h)
+		pf.Value = pf.Value.Add(h.Value)
+		pf.Cost = pf.Cost.Add(h.Cost)
+		pf.Gain = pf.Gain.Add(h.Gain)
+		flows = append(flows, hflows...)
+		complete = complete && hflows != nil
+	}
+
+	for i := range pf.Holdings {
+		if !pf.Value.IsZero() {
+			pf.Holdings[i].Share = percent(pf.Holdings[i].Value.Float64() / pf.Value.Float64())
+		}
+	}
+	if r, ok := xirr(flows); ok && complete {
+		r = percent(r)
+		pf.Return = &r
+	}
+	sort.Strings(pf.Unpriced)
+	return pf
+}
+
+// holding values an investment account and returns the cash flows used for its return, nil when one of them
+// can not be converted to opts.Currency.
+// Accounts without units at opts.To nor splits during the window are skipped, as are the accounts without price at opts.To.
+func (b *Book) holding(act *Account, opts BalanceOptions, unpriced *[]string) (Holding, []cashFlow, bool) {
+	h := Holding{Account: act.Ref(), Type: act.Type, Commodity: act.Commodity}
+	h.Quantity = act.Balance(BalanceOptions{To: opts.To, Quantity: true}).Amount
+
+	// value returns the market value of quantity at date
+	value := func(quantity Amount, date string) (Amount, bool) {
+		if quantity.IsZero() {
+			return Amount{SCU: opts.Currency.Fraction}, true
+		}
+		v, ok := opts.Prices.Convert(quantity, act.Commodity, opts.Currency, date)
+		if !ok {
+			*unpriced = appendOnce(*unpriced, act.Commodity.String())
+		}
+		return v, ok
+	}
+	flows := make([]cashFlow, 0)
+	priced := true // false when a cash flow of the window is unknown, so is the return
+	if opts.From != "" {
+		initial := act.Balance(BalanceOptions{To: previousDay(opts.From), Quantity: true}).Amount
+		if !initial.IsZero() {
+			v, ok := value(initial, previousDay(opts.From))
+			priced = priced && ok
+			flows = append(flows, newCashFlow(opts.From, -v.Float64()))
+		}
+	}
+	for _, s := range act.Splits {
+		t := s.Transaction
+		if t.DatePosted < opts.From || t.DatePosted > opts.To || s.Quantity.IsZero() {
+			continue
+		}
+		if s.Quantity.Sign() < 0 {
+			proceeds, ok := convertSplitValue(saleProceeds(s), s, opts, unpriced)
+			priced = priced && ok
+			flows = append(flows, newCashFlow(t.DatePosted, proceeds.Float64()))
+		} else {
+			cost, ok := convertSplitValue(s.Value, s, opts, unpriced)
+			priced = priced && ok
+			flows = append(flows, newCashFlow(t.DatePosted, -cost.Float64()))
+		}
+	}
+	if h.Quantity.IsZero() && len(flows) == 0 {
+		return h, nil, false
+	}
+
+	v, ok := value(h.Quantity, opts.To)
+	if !ok {
+		return h, nil, false
+	}
+	h.Value = v
+	if rate, ok := opts.Prices.Rate(act.Commodity, opts.Currency, opts.To); ok && !h.Quantity.IsZero() {
+		price := rate.Round(1000000).WithSCU(opts.Currency.Fraction)
+		h.Price = &price
+	}
+
+	h.Cost = Amount{SCU: opts.Currency.Fraction}
+	for _, l := range act.AllLots() {
+		st := l.Status(BalanceOptions{To: opts.To, Currency: opts.Currency, Prices: opts.Prices})
+		for _, c := range st.Unpriced {
+			*unpriced = appendOnce(*unpriced, c)
+		}
+		h.Cost = h.Cost.Add(st.Cost)
+	}
+	h.Gain = h.Value.Sub(h.Cost)
+
+	if !priced {
+		return h, nil, true
+	}
+	flows = append(flows, newCashFlow(opts.To, h.Value.Float64()))
+	if r, ok := xirr(flows); ok {
+		r = percent(r)
+		h.Return = &r
+	}
+	return h, flows, true
+}
+
+func newCashFlow(date string, amount float64) cashFlow {
+	d, _ := time.Parse("2006-01-02", date)
+	return cashFlow{date: d, amount: amount}
+}
+
+// previousDay returns the day before date
+func previousDay(date string) string {
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return d.AddDate(0, 0, -1).Format("2006-01-02")
+}
+
+// percent returns x in percentage rounded to 2 decimals
+func percent(x float64) float64 {
+	return math.Round(x*10000) / 100
+}
+
+// xirr returns the annual rate making the net present value of the cash flows equal to zero.
+// It needs at least one negative and one positive flow.
+func xirr(flows []cashFlow) (float64, bool) {
+	var neg, pos bool
+	for _, f := range flows {
+		neg = neg || f.amount < 0
+		pos = pos || f.amount > 0
+	}
+	if !neg || !pos {
+		return 0, false
+	}
+	sort.SliceStable(flows, func(i, j int) bool { return flows[i].date.Before(flows[j].date) })
+	start := flows[0].date
+	if !flows[len(flows)-1].date.After(start) {
+		return 0, false
+	}
+	npv := func(rate float64) float64 {
+		var sum float64
+		for _, f := range flows {
+			years := f.date.Sub(start).Hours() / 24 / 365
+			sum += f.amount / math.Pow(1+rate, years)
+		}
+		return sum
+	}
+
+	// the net present value decreases with the rate when money is invested first: find a bracket then bisect
+	low, high := -0.999999, 1.0
+	for npv(high) > 0 && high < 1e6 {
+		high = high * 2
+	}
+	if npv(low)*npv(high) > 0 {
+		return 0, false
+	}
+	for i := 0; i < 200 && high-low > 1e-10; i++ {
+		mid := (low + high) / 2
+		if npv(low)*npv(mid) <= 0 {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	return (low + high) / 2, true
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var xirrTests = []struct {
+	flows []cashFlow
+	rate  float64
+	ok    bool
+}{
+	{[]cashFlow{newCashFlow("2019-01-01", -100), newCashFlow("2020-01-01", 110)}, 10, true},
+	{[]cashFlow{newCashFlow("2019-01-01", -100), newCashFlow("2020-01-01", 90)}, -10, true},
+	{[]cashFlow{newCashFlow("2020-01-01", 110), newCashFlow("2019-01-01", -100)}, 10, true},
+	{[]cashFlow{newCashFlow("2019-01-01", -100), newCashFlow("2020-01-01", -10)}, 0, false},
+	{[]cashFlow{newCashFlow("2019-01-01", -100), newCashFlow("2019-01-01", 110)}, 0, false},
+	{[]cashFlow{}, 0, false},
+}
+
+func TestXIRR(t *testing.T) {
+	for _, tt := range xirrTests {
+		rate, ok := xirr(tt.flows)
+		assert.Equal(t, tt.ok, ok, "Problem with XIRR of %v", tt.flows)
+		if ok {
+			assert.Equal(t, tt.rate, percent(rate), "Problem with XIRR of %v", tt.flows)
+		}
+	}
+}
+
+func TestPortfolio(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pf := book.Portfolio(BalanceOptions{To: "2019-03-31"})
+	assert.Equal(t, "CURRENCY:EUR", pf.Currency, "Portfolio should be valued in the currency of the root account by default")
+	if assert.Equal(t, 1, len(pf.Holdings), "Problem with the number of holdings") {
+		h := pf.Holdings[0]
+		assert.Equal(t, "AAPL", h.Account.Name, "Problem with account of holding")
+		assert.Equal(t, "3", h.Quantity.String(), "Problem with quantity of %s", h.Account.Name)
+		if assert.NotNil(t, h.Price, "Problem with price of %s", h.Account.Name) {
+			assert.Equal(t, "150.442478", h.Price.String(), "Problem with price of %s", h.Account.Name)
+		}
+		assert.Equal(t, "451.33", h.Value.String(), "Problem with value of %s", h.Account.Name)
+		assert.Equal(t, "401.78", h.Cost.String(), "Problem with cost of %s", h.Account.Name)
+		assert.Equal(t, "49.55", h.Gain.String(), "Problem with gain of %s", h.Account.Name)
+		assert.Equal(t, 100.0, h.Share, "Problem with share of %s", h.Account.Name)
+	}
+	assert.Equal(t, "451.33", pf.Value.String(), "Problem with value of portfolio")
+	assert.Empty(t, pf.Unpriced, "Problem with unpriced commodities")
+
+	usd := book.Commodities.Find(CurrencySpace, "USD")
+	pf = book.Portfolio(BalanceOptions{To: "2019-03-31", Currency: usd})
+	assert.Equal(t, "510.00", pf.Value.String(), "Problem with value of portfolio in USD")
+	assert.Equal(t, "60.00", pf.Gain.String(), "Problem with gain of portfolio in USD")
+	if assert.NotNil(t, pf.Return, "Problem with return of portfolio") {
+		assert.Equal(t, 171.72, *pf.Return, "Problem with return of portfolio")
+	}
+
+	pf = book.Portfolio(BalanceOptions{From: "2019-02-15", To: "2019-03-31", Currency: usd})
+	if assert.NotNil(t, pf.Return, "Problem with return of portfolio over a window") {
+		assert.Equal(t, 325.1, *pf.Return, "Return over a window should start from the value of the holdings")
+	}
+
+	pf = book.Portfolio(BalanceOptions{To: "2019-01-31"})
+	assert.Empty(t, pf.Holdings, "Accounts without units nor splits should be skipped")
+
+	broker := testBroker()
+	pf = broker.Portfolio(BalanceOptions{To: "2019-03-31", Currency: broker.Commodities[0]})
+	if assert.Equal(t, 1, len(pf.Holdings), "A position closed during the window should be listed") {
+		h := pf.Holdings[0]
+		assert.Equal(t, "0", h.Quantity.String(), "Problem with quantity of a closed position")
+		assert.Nil(t, h.Price, "Problem with price of a closed position")
+		if assert.NotNil(t, h.Return, "Problem with return of a closed position") {
+			assert.Equal(t, -8.35, *h.Return, "Problem with return of a closed position")
+		}
+	}
+
+	// purchases and sales in USD can not be converted to EUR without price
+	eur := &Commodity{Space: CurrencySpace, ID: "EUR", Fraction: 100}
+	pf = broker.Portfolio(BalanceOptions{To: "2019-03-31", Currency: eur, Prices: NewPriceDB(nil)})
+	if assert.Equal(t, 1, len(pf.Holdings), "A holding with unpriced cash flows should be listed") {
+		assert.Nil(t, pf.Holdings[0].Return, "Return of a holding with unpriced cash flows should be unknown")
+	}
+	assert.Nil(t, pf.Return, "Return of a portfolio with unpriced cash flows should be unknown")
+	assert.Equal(t, []string{"CURRENCY:USD"}, pf.Unpriced, "Problem with unpriced commodities")
+}
@@ -0,0 +1,128 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+)
+
+// Price is the value of one unit of Commodity expressed in Currency at a given Date
+type Price struct {
+	Commodity *Commodity `json:"commodity"`
+	Currency  *Commodity `json:"currency"`
+	Date      string     `json:"date"` // YYYY-MM-DD
+	Value     Amount     `json:"value"`
+	Source    string     `json:"source,omitempty"`
+	Type      string     `json:"type,omitempty"`
+}
+
+type priceKey struct {
+	commodity *Commodity
+	currency  *Commodity
+}
+
+// PriceDB is the prices database of a book, indexed by commodity and currency
+type PriceDB struct {
+	prices []*Price
+	index  map[priceKey][]*Price // sorted by date
+}
+
+// NewPriceDB returns a prices database indexing prices
+func NewPriceDB(prices []*Price) *PriceDB {
+	db := &PriceDB{prices: prices, index: make(map[priceKey][]*Price)}
+	for _, p := range prices {
+		k := priceKey{commodity: p.Commodity, currency: p.Currency}
+		db.index[k] = append(db.index[k], p)
+	}
+	for _, l := range db.index {
+		sort.SliceStable(l, func(i, j int) bool { return l[i].Date < l[j].Date })
+	}
+	return db
+}
+
+// Prices returns all the prices of the database
+func (db *PriceDB) Prices() []*Price {
+	if db == nil {
+		return nil
+	}
+	return db.prices
+}
+
+// Latest returns the most recent price of commodity in currency at or before date
+func (db *PriceDB) Latest(commodity *Commodity, currency *Commodity, date string) *Price {
+	if db == nil {
+		return nil
+	}
+	l := db.index[priceKey{commodity: commodity, currency: currency}]
+	i := sort.Search(len(l), func(i int) bool { return l[i].Date > date })
+	if i == 0 {
+		return nil
+	}
+	return l[i-1]
+}
+
+// Rate returns the exchange rate from a commodity to another at date.
+// Like GnuCash, it uses a direct price, a reversed price or goes through an intermediate commodity.
+func (db *PriceDB) Rate(from *Commodity, to *Commodity, date string) (Amount, bool) {
+	if from == to {
+		return NewAmount(1, 1), true
+	}
+	if rate, ok := db.directRate(from, to, date); ok {
+		return rate, true
+	}
+	if db == nil {
+		return Amount{}, false
+	}
+	for _, p := range db.prices {
+		if p.Commodity != from && p.Currency != from {
+			continue
+		}
+		via := p.Currency
+		if p.Currency == from {
+			via = p.Commodity
+		}
+		if via == to {
+			continue
+		}
+		r1, ok := db.directRate(from, via, date)
+		if !ok {
+			continue
+		}
+		r2, ok := db.directRate(via, to, date)
+		if !ok {
+			continue
+		}
+		return r1.Mul(r2), true
+	}
+	return Amount{}, false
+}
+
+func (db *PriceDB) directRate(from *Commodity, to *Commodity, date string) (Amount, bool) {
+	p := db.Latest(from, to, date)
+	r := db.Latest(to, from, date)
+	switch {
+	case p != nil && (r == nil || p.Date >= r.Date):
+		return p.Value, true
+	case r != nil && !r.Value.IsZero():
+		return r.Value.Inv(), true
+	}
+	return Amount{}, false
+}
+
+// Convert converts an amount of a commodity to another using the rate at date.
+// The result is rounded to the fraction of the target commodity.
+func (db *PriceDB) Convert(a Amount, from *Commodity, to *Commodity, date string) (Amount, bool) {
+	if from == to {
+		return a, true
+	}
+	rate, ok := db.Rate(from, to, date)
+	if !ok {
+		return Amount{}, false
+	}
+	fraction := to.Fraction
+	if fraction == 0 {
+		fraction = 100
+	}
+	return a.Mul(rate).Round(fraction), true
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	eur  = &Commodity{Space: CurrencySpace, ID: "EUR", Fraction: 100}
+	usd  = &Commodity{Space: CurrencySpace, ID: "USD", Fraction: 100}
+	aapl = &Commodity{Space: "NASDAQ", ID: "AAPL", Fraction: 1}
+)
+
+func testPriceDB() *PriceDB {
+	return NewPriceDB([]*Price{
+		{Commodity: aapl, Currency: usd, Date: "2019-03-01", Value: NewAmount(17000, 100)},
+		{Commodity: aapl, Currency: usd, Date: "2019-02-01", Value: NewAmount(15000, 100)},
+		{Commodity: eur, Currency: usd, Date: "2019-01-10", Value: NewAmount(125, 100)},
+		{Commodity: eur, Currency: usd, Date: "2019-03-01", Value: NewAmount(113, 100)},
+	})
+}
+
+var priceConvertTests = []struct {
+	amount   Amount
+	from     *Commodity
+	to       *Commodity
+	date     string
+	ok       bool
+	expected string
+}{
+	{NewAmount(10000, 100), eur, eur, "2019-01-01", true, "100.00"},
+	{NewAmount(10000, 100), eur, usd, "2019-02-01", true, "125.00"},
+	{NewAmount(10000, 100), eur, usd, "2019-03-01", true, "113.00"},
+	{NewAmount(12500, 100), usd, eur, "2019-02-01", true, "100.00"},
+	{NewAmount(2, 1), aapl, usd, "2019-02-15", true, "300.00"},
+	{NewAmount(2, 1), aapl, eur, "2019-02-15", true, "240.00"},
+	{NewAmount(2, 1), aapl, usd, "2019-01-15", false, ""},
+	{NewAmount(10000, 100), eur, usd, "2019-01-01", false, ""},
+}
+
+func TestPriceDB(t *testing.T) {
+	db := testPriceDB()
+	assert.Equal(t, 4, len(db.Prices()), "Problem with the number of prices")
+
+	p := db.Latest(aapl, usd, "2019-02-28")
+	if assert.NotNil(t, p, "Problem while retrieve latest price") {
+		assert.Equal(t, "2019-02-01", p.Date, "Problem with the date of latest price")
+	}
+	assert.Nil(t, db.Latest(aapl, usd, "2019-01-31"), "Problem while retrieve price before first one")
+	assert.Nil(t, db.Latest(usd, aapl, "2019-03-31"), "Problem while retrieve not existing price")
+
+	for _, tt := range priceConvertTests {
+		a, ok := db.Convert(tt.amount, tt.from, tt.to, tt.date)
+		assert.Equal(t, tt.ok, ok, "Problem while converting %s from %s to %s at %s", tt.amount, tt.from, tt.to, tt.date)
+		if ok {
+			assert.Equal(t, tt.expected, a.String(), "Problem while converting %s from %s to %s at %s", tt.amount, tt.from, tt.to, tt.date)
+		}
+	}
+}
+
+func TestPriceDBHighPrecision(t *testing.T) {
+	// prices written by Finance::Quote can have numerators and denominators close to the limits of int64
+	db := NewPriceDB([]*Price{
+		{Commodity: eur, Currency: usd, Date: "2019-01-01", Value: NewAmount(8571428571428571, 10000000000000000)},
+		{Commodity: eur, Currency: usd, Date: "2019-02-01", Value: NewAmount(1234567891, 1000000000)},
+	})
+
+	a, ok := db.Convert(NewAmount(1234, 100), eur, usd, "2019-01-15")
+	if assert.True(t, ok, "Problem while converting with a high precision price") {
+		assert.Equal(t, "10.58", a.String(), "Problem while converting with a high precision price")
+	}
+	a, ok = db.Convert(NewAmount(9876543210, 100), eur, usd, "2019-02-15")
+	if assert.True(t, ok, "Problem while converting a large amount") {
+		assert.Equal(t, "121932631.21", a.String(), "Problem while converting a large amount")
+	}
+	a, ok = db.Convert(NewAmount(12193263121, 100), usd, eur, "2019-02-15")
+	if assert.True(t, ok, "Problem while converting a large amount with the inverse price") {
+		assert.Equal(t, 1, a.Sign(), "A conversion should not change the sign of an amount")
+	}
+}
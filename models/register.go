@@ -0,0 +1,94 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// AccountRef is a short reference to an account
+type AccountRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// RegisterEntry is a line of an account register: one split with the running balance of the account
+type RegisterEntry struct {
+	Date         string       `json:"date"`
+	Num          string       `json:"num,omitempty"`
+	Description  string       `json:"description"`
+	Memo         string       `json:"memo,omitempty"`
+	Transaction  string       `json:"transaction"`
+	Account      AccountRef   `json:"account"`
+	Counterparts []AccountRef `json:"counterparts"`
+	Amount       Amount       `json:"amount"`
+	Balance      Amount       `json:"balance"`
+}
+
+// Register returns the splits of the account sorted by date, with the running balance.
+// Splits of sub-accounts are included when opts.Recursive is set. The running balance
+// starts with the balance of the account before opts.From.
+func (a *Account) Register(opts BalanceOptions) []RegisterEntry {
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+
+	acts := []*Account{a}
+	if opts.Recursive {
+		acts = append(acts, a.Descendants()...)
+	}
+	type accountSplit struct {
+		*Split
+		act *Account
+	}
+	splits := make([]accountSplit, 0)
+	for _, act := range acts {
+		for _, s := range act.Splits {
+			splits = append(splits, accountSplit{Split: s, act: act})
+		}
+	}
+	sort.SliceStable(splits, func(i, j int) bool {
+		ti, tj := splits[i].Transaction, splits[j].Transaction
+		if ti.DatePosted != tj.DatePosted {
+			return ti.DatePosted < tj.DatePosted
+		}
+		return ti.DateEntered < tj.DateEntered
+	})
+
+	entries := make([]RegisterEntry, 0)
+	balance := Amount{SCU: a.SCU}
+	for _, s := range splits {
+		t := s.Transaction
+		if t.DatePosted > opts.To {
+			break
+		}
+		if opts.Type != "" && t.Num != opts.Type {
+			continue
+		}
+		balance = balance.Add(s.Amount(opts))
+		if t.DatePosted < opts.From {
+			continue
+		}
+
+		entry := RegisterEntry{
+			Date:         t.DatePosted,
+			Num:          t.Num,
+			Description:  t.Description,
+			Memo:         s.Memo,
+			Transaction:  t.ID,
+			Account:      s.act.Ref(),
+			Counterparts: make([]AccountRef, 0),
+			Amount:       s.Amount(opts),
+			Balance:      balance,
+		}
+		for _, other := range t.Splits {
+			if other != s.Split && other.Account != nil {
+				entry.Counterparts = append(entry.Counterparts, other.Account.Ref())
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	root, _ := testTransactions()
+	bank := root.FindByID("1")
+
+	entries := bank.Register(BalanceOptions{})
+	if assert.Equal(t, 3, len(entries), "Problem with the number of register entries") {
+		assert.Equal(t, "Gas station", entries[0].Description, "Problem with register entry description")
+		assert.Equal(t, "-60.00", entries[0].Amount.String(), "Problem with register entry amount")
+		assert.Equal(t, "-60.00", entries[0].Balance.String(), "Problem with register entry balance")
+		assert.Equal(t, []AccountRef{{ID: "2", Name: "Fuel"}}, entries[0].Counterparts, "Problem with register entry counterparts")
+		assert.Equal(t, "-105.50", entries[1].Balance.String(), "Problem with register running balance")
+		assert.Equal(t, 2, len(entries[2].Counterparts), "Problem with register entry counterparts")
+		assert.Equal(t, "-235.50", entries[2].Balance.String(), "Problem with register running balance")
+	}
+
+	entries = bank.Register(BalanceOptions{From: "2019-01-06", To: "2019-01-31"})
+	if assert.Equal(t, 1, len(entries), "Problem with the number of register entries between 2 dates") {
+		assert.Equal(t, "-45.50", entries[0].Amount.String(), "Problem with register entry amount")
+		assert.Equal(t, "-105.50", entries[0].Balance.String(), "Problem with register balance including opening balance")
+	}
+
+	entries = root.Register(BalanceOptions{Recursive: true})
+	if assert.Equal(t, 7, len(entries), "Problem with the number of entries of a recursive register") {
+		assert.Equal(t, "Bank", entries[0].Account.Name, "Problem with account of register entry")
+		assert.Equal(t, "Fuel", entries[1].Account.Name, "Problem with account of register entry")
+		assert.True(t, entries[6].Balance.IsZero(), "Problem with balance of a recursive register")
+	}
+
+	assert.Equal(t, 0, len(root.Register(BalanceOptions{})), "Problem with register of an account without splits")
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+// Account types grouped as in the reports of GnuCash
+var (
+	AssetTypes     = []string{"ASSET", "BANK", "CASH", "CURRENCY", "STOCK", "MUTUAL", "RECEIVABLE"}
+	LiabilityTypes = []string{"CREDIT", "LIABILITY", "PAYABLE"}
+	EquityTypes    = []string{"EQUITY", "TRADING"}
+	IncomeTypes    = []string{"INCOME"}
+	ExpenseTypes   = []string{"EXPENSE"}
+	CashTypes      = []string{"BANK", "CASH"}
+
+	InvestmentTypes = []string{"STOCK", "MUTUAL"}
+)
+
+// ReportLine is an account of a report with the amount of its own splits
+// and the total of its sub-accounts included in the report.
+// Compare fields are set when the report is compared to another period.
+type ReportLine struct {
+	Account       AccountRef    `json:"account"`
+	Type          string        `json:"type"`
+	Amount        Amount        `json:"amount"`
+	Total         Amount        `json:"total"`
+	CompareAmount *Amount       `json:"compare_amount,omitempty"`
+	CompareTotal  *Amount       `json:"compare_total,omitempty"`
+	Children      []*ReportLine `json:"children,omitempty"`
+}
+
+// ReportSection is a group of sub-trees of accounts with its total
+type ReportSection struct {
+	Total        Amount        `json:"total"`
+	CompareTotal *Amount       `json:"compare_total,omitempty"`
+	Accounts     []*ReportLine `json:"accounts"`
+}
+
+// compareWith sets the compare fields of the section from the same section computed for another period
+func (s *ReportSection) compareWith(other ReportSection) {
+	total := other.Total
+	s.CompareTotal = &total
+	compareLines(s.Accounts, other.Accounts)
+}
+
+func compareLines(lines []*ReportLine, others []*ReportLine) {
+	for i, line := range lines {
+		if i >= len(others) || others[i].Account.ID != line.Account.ID {
+			continue
+		}
+		amount, total := others[i].Amount, others[i].Total
+		line.CompareAmount, line.CompareTotal = &amount, &total
+		compareLines(line.Children, others[i].Children)
+	}
+}
+
+// newReportSection returns the sub-trees of accounts under root whose type is in types.
+// An account starts a new sub-tree when its parent is not of one of these types.
+// With reverse, amounts are negated as GnuCash does to show credit accounts (liabilities, equity,
+// income) as positive numbers. The list of commodities without price is returned with the section.
+func newReportSection(root *Account, types []string, opts BalanceOptions, reverse bool) (ReportSection, []string) {
+	opts.Recursive = false
+	section := ReportSection{Accounts: make([]*ReportLine, 0)}
+	var unpriced []string
+	if root == nil {
+		return section, unpriced
+	}
+	tops := root.WalkBFS(func(act *Account) bool {
+		return hasType(types, act.Type) && (act.Parent == nil || !hasType(types, act.Parent.Type))
+	})
+	for _, act := range tops {
+		line := newReportLine(act, types, opts, reverse, &unpriced)
+		section.Total = section.Total.Add(line.Total)
+		section.Accounts = append(section.Accounts, line)
+	}
+	return section, unpriced
+}
+
+func newReportLine(act *Account, types []string, opts BalanceOptions, reverse bool, unpriced *[]string) *ReportLine {
+	b := act.Balance(opts)
+	for _, c := range b.Unpriced {
+		*unpriced = appendOnce(*unpriced, c)
+	}
+	if reverse {
+		b.Amount = b.Amount.Neg()
+	}
+	line := &ReportLine{Account: act.Ref(), Type: act.Type, Amount: b.Amount, Total: b.Amount}
+	for _, child := range act.Children {
+		if !hasType(types, child.Type) {
+			continue
+		}
+		cl := newReportLine(child, types, opts, reverse, unpriced)
+		line.Total = line.Total.Add(cl.Total)
+		line.Children = append(line.Children, cl)
+	}
+	return line
+}
+
+func hasType(types []string, atype string) bool {
+	for _, t := range types {
+		if t == atype {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Periods of recurrences
+const (
+	Once         = "once"
+	Daily        = "day"
+	Weekly       = "week"
+	Monthly      = "month"
+	EndOfMonth   = "end of month"
+	NthWeekday   = "nth weekday"  // like the 2nd tuesday of the month
+	LastWeekday  = "last weekday" // like the last friday of the month
+	Yearly       = "year"
+	WeekendNone  = "none"
+	WeekendBack  = "back"    // to the previous friday
+	WeekendAhead = "forward" // to the next monday
+)
+
+// maxOccurrences limits the number of occurrences computed for a recurrence
+const maxOccurrences = 100000
+
+// Recurrence is a rule of a schedule: every Multiplier Period starting at Start.
+// Occurrences falling on a week-end can be moved to the previous or the next working day.
+type Recurrence struct {
+	Period        string `json:"period"`
+	Multiplier    int    `json:"multiplier"`
+	Start         string `json:"start"`
+	WeekendAdjust string `json:"weekend_adjust,omitempty"`
+}
+
+// ScheduledSplit is a split of the template of a scheduled transaction.
+// Amount is positive for a debit. Formula is set when the amount is computed by GnuCash from a formula.
+type ScheduledSplit struct {
+	Account AccountRef `json:"account"`
+	Memo    string     `json:"memo,omitempty"`
+	Amount  Amount     `json:"amount"`
+	Formula string     `json:"formula,omitempty"`
+}
+
+// ScheduledTemplate is a transaction created at each occurrence of a scheduled transaction
+type ScheduledTemplate struct {
+	Description string           `json:"description"`
+	Num         string           `json:"num,omitempty"`
+	Currency    *Commodity       `json:"currency,omitempty"`
+	Splits      []ScheduledSplit `json:"splits"`
+}
+
+// ScheduledTransaction is a transaction GnuCash creates regularly from templates.
+// Last is the date of the last occurrence already created.
+// Remaining is the number of occurrences left when the number of occurrences is limited.
+type ScheduledTransaction struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Enabled     bool                `json:"enabled"`
+	AutoCreate  bool                `json:"auto_create"`
+	Start       string              `json:"start"`
+	End         string              `json:"end,omitempty"`
+	Last        string              `json:"last,omitempty"`
+	Occurrences int                 `json:"occurrences,omitempty"`
+	Remaining   int                 `json:"remaining,omitempty"`
+	Recurrences []Recurrence        `json:"recurrences"`
+	Templates   []ScheduledTemplate `json:"templates"`
+}
+
+// Occurrence is a future transaction created by a scheduled transaction
+type Occurrence struct {
+	Date      string `json:"date"`
+	Scheduled string `json:"scheduled"` // ID of the scheduled transaction
+	Name      string `json:"name"`
+	ScheduledTemplate
+}
+
+// Upcoming returns the occurrences of the enabled scheduled transactions of the book
+// between from and until, sorted by date.
+// from defaults to today and until to one month after from.
+func (b *Book) Upcoming(from string, until string) []Occurrence {
+	if from == "" {
+		from = time.Now().Format("2006-01-02")
+	}
+	if until == "" {
+		if d, err := time.Parse("2006-01-02", from); err == nil {
+			until = d.AddDate(0, 1, 0).Format("2006-01-02")
+		}
+	}
+	occurrences := make([]Occurrence, 0)
+	for _, o := range b.pending(until) {
+		if o.Date >= from {
+			occurrences = append(occurrences, o)
+		}
+	}
+	return occurrences
+}
+
+// pending returns the occurrences of the enabled scheduled transactions not yet created up to until,
+// including the overdue ones, sorted by date
+func (b *Book) pending(until string) []Occurrence {
+	occurrences := make([]Occurrence, 0)
+	for _, sx := range b.Scheduled {
+		if !sx.Enabled {
+			continue
+		}
+		for _, date := range sx.Dates(until) {
+			for _, t := range sx.Templates {
+				occurrences = append(occurrences, Occurrence{Date: date, Scheduled: sx.ID, Name: sx.Name, ScheduledTemplate: t})
+			}
+		}
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool { return occurrences[i].Date < occurrences[j].Date })
+	return occurrences
+}
+
+// Dates returns the dates of the occurrences of the scheduled transaction not yet created, up to until
+func (sx *ScheduledTransaction) Dates(until string) []string {
+	if sx.End != "" && sx.End < until {
+		until = sx.End
+	}
+	set := make(map[string]bool)
+	for _, r := range sx.Recurrences {
+		for _, d := range r.Dates(until) {
+			set[d] = true
+		}
+	}
+	all := make([]string, 0, len(set))
+	for d := range set {
+		all = append(all, d)
+	}
+	sort.Strings(all)
+
+	dates := make([]string, 0)
+	for _, d := range all {
+		if d < sx.Start || (sx.Last != "" && d <= sx.Last) {
+			continue
+		}
+		if sx.Occurrences > 0 && len(dates) >= sx.Remaining {
+			break
+		}
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// Dates returns the dates of the recurrence from its start up to until
+func (r Recurrence) Dates(until string) []string {
+	dates := make([]string, 0)
+	start, err := time.Parse("2006-01-02", r.Start)
+	if err != nil {
+		return dates
+	}
+	end, err := time.Parse("2006-01-02", until)
+	if err != nil {
+		return dates
+	}
+	mult := r.Multiplier
+	if mult < 1 {
+		mult = 1
+	}
+
+	for k := 0; k < maxOccurrences; k++ {
+		d, ok := r.occurrence(start, k*mult)
+		if !ok || d.After(end) && r.adjust(d).After(end) {
+			break
+		}
+		if d = r.adjust(d); !d.After(end) {
+			dates = append(dates, d.Format("2006-01-02"))
+		}
+		if r.Period == Once {
+			break
+		}
+	}
+	return dates
+}
+
+// occurrence returns the date n periods after start
+func (r Recurrence) occurrence(start time.Time, n int) (time.Time, bool) {
+	switch r.Period {
+	case Once:
+		return start, true
+	case Daily:
+		return start.AddDate(0, 0, n), true
+	case Weekly:
+		return start.AddDate(0, 0, 7*n), true
+	case Monthly:
+		return addMonths(start, n, start.Day()), true
+	case EndOfMonth:
+		return addMonths(start, n, 31), true
+	case NthWeekday, LastWeekday:
+		first := addMonths(start, n, 1)
+		offset := (int(start.Weekday()) - int(first.Weekday()) + 7) % 7
+		d := first.AddDate(0, 0, offset+7*((start.Day()-1)/7))
+		if r.Period == LastWeekday || d.Month() != first.Month() {
+			// last occurrence of the weekday in the month
+			last := addMonths(start, n, 31)
+			d = last.AddDate(0, 0, -((int(last.Weekday()) - int(start.Weekday()) + 7) % 7))
+		}
+		return d, true
+	case Yearly:
+		return addMonths(start, 12*n, start.Day()), true
+	}
+	return time.Time{}, false
+}
+
+// addMonths returns the date n months after d, on day or on the last day of the month if it is shorter
+func addMonths(d time.Time, n int, day int) time.Time {
+	first := time.Date(d.Year(), d.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// adjust moves a date falling on a week-end depending on WeekendAdjust
+func (r Recurrence) adjust(d time.Time) time.Time {
+	switch {
+	case r.WeekendAdjust == WeekendBack && d.Weekday() == time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case r.WeekendAdjust == WeekendBack && d.Weekday() == time.Sunday:
+		return d.AddDate(0, 0, -2)
+	case r.WeekendAdjust == WeekendAhead && d.Weekday() == time.Saturday:
+		return d.AddDate(0, 0, 2)
+	case r.WeekendAdjust == WeekendAhead && d.Weekday() == time.Sunday:
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
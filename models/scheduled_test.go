@@ -0,0 +1,94 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var recurrenceTests = []struct {
+	recurrence Recurrence
+	until      string
+	dates      []string
+}{
+	{Recurrence{Period: Monthly, Multiplier: 1, Start: "2019-01-31"}, "2019-04-30", []string{"2019-01-31", "2019-02-28", "2019-03-31", "2019-04-30"}},
+	{Recurrence{Period: EndOfMonth, Multiplier: 1, Start: "2019-01-15"}, "2019-04-30", []string{"2019-01-31", "2019-02-28", "2019-03-31", "2019-04-30"}},
+	{Recurrence{Period: Weekly, Multiplier: 2, Start: "2019-06-03"}, "2019-07-01", []string{"2019-06-03", "2019-06-17", "2019-07-01"}},
+	{Recurrence{Period: Daily, Multiplier: 10, Start: "2019-06-01"}, "2019-06-30", []string{"2019-06-01", "2019-06-11", "2019-06-21"}},
+	{Recurrence{Period: NthWeekday, Multiplier: 1, Start: "2019-06-11"}, "2019-08-31", []string{"2019-06-11", "2019-07-09", "2019-08-13"}},
+	{Recurrence{Period: NthWeekday, Multiplier: 1, Start: "2019-06-29"}, "2019-07-31", []string{"2019-06-29", "2019-07-27"}},
+	{Recurrence{Period: LastWeekday, Multiplier: 1, Start: "2019-06-28"}, "2019-08-31", []string{"2019-06-28", "2019-07-26", "2019-08-30"}},
+	{Recurrence{Period: Yearly, Multiplier: 1, Start: "2020-02-29"}, "2022-12-31", []string{"2020-02-29", "2021-02-28", "2022-02-28"}},
+	{Recurrence{Period: Once, Multiplier: 1, Start: "2019-06-15"}, "2019-12-31", []string{"2019-06-15"}},
+	{Recurrence{Period: Monthly, Multiplier: 1, Start: "2019-06-15", WeekendAdjust: WeekendBack}, "2019-08-31", []string{"2019-06-14", "2019-07-15", "2019-08-15"}},
+	{Recurrence{Period: Monthly, Multiplier: 1, Start: "2019-06-15", WeekendAdjust: WeekendAhead}, "2019-08-31", []string{"2019-06-17", "2019-07-15", "2019-08-15"}},
+	{Recurrence{Period: Monthly, Multiplier: 1, Start: "2019-06-15"}, "2019-06-14", []string{}},
+	{Recurrence{Period: "fortnight", Multiplier: 1, Start: "2019-06-15"}, "2019-08-31", []string{}},
+}
+
+func TestRecurrenceDates(t *testing.T) {
+	for _, tt := range recurrenceTests {
+		assert.Equal(t, tt.dates, tt.recurrence.Dates(tt.until), "Problem with dates of %v", tt.recurrence)
+	}
+}
+
+func TestScheduledTransactionDates(t *testing.T) {
+	sx := ScheduledTransaction{
+		Start:       "2019-07-01",
+		End:         "2019-10-15",
+		Last:        "2019-07-10",
+		Recurrences: []Recurrence{{Period: Monthly, Multiplier: 1, Start: "2019-06-10"}},
+	}
+	assert.Equal(t, []string{"2019-08-10", "2019-09-10", "2019-10-10"}, sx.Dates("2019-12-31"), "Problem with dates after the last occurrence")
+
+	sx.Occurrences, sx.Remaining = 5, 2
+	assert.Equal(t, []string{"2019-08-10", "2019-09-10"}, sx.Dates("2019-12-31"), "Problem with the number of remaining occurrences")
+
+	sx = ScheduledTransaction{
+		Start: "2019-07-01",
+		Recurrences: []Recurrence{
+			{Period: Monthly, Multiplier: 1, Start: "2019-07-01"},
+			{Period: Monthly, Multiplier: 1, Start: "2019-07-15"},
+		},
+	}
+	assert.Equal(t, []string{"2019-07-01", "2019-07-15", "2019-08-01"}, sx.Dates("2019-08-10"), "Problem with several recurrences")
+}
+
+func TestLoadScheduled(t *testing.T) {
+	book, err := LoadFromFile("testdata/empty.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !assert.Equal(t, 1, len(book.Scheduled), "Problem with the number of scheduled transactions") {
+		return
+	}
+	sx := book.Scheduled[0]
+	assert.Equal(t, "Netflix", sx.Name, "Problem with name of scheduled transaction")
+	assert.True(t, sx.Enabled, "Problem with enabled flag of %s", sx.Name)
+	assert.Equal(t, "2019-06-22", sx.Start, "Problem with start of %s", sx.Name)
+	assert.Equal(t, []Recurrence{{Period: Monthly, Multiplier: 1, Start: "2019-06-10"}}, sx.Recurrences, "Problem with recurrences of %s", sx.Name)
+	if assert.Equal(t, 1, len(sx.Templates), "Problem with templates of %s", sx.Name) {
+		tmpl := sx.Templates[0]
+		assert.Equal(t, "Netflix", tmpl.Description, "Problem with description of template")
+		if assert.Equal(t, 2, len(tmpl.Splits), "Problem with splits of template") {
+			assert.Equal(t, "Checking Account", tmpl.Splits[0].Account.Name, "Problem with account of template split")
+			assert.Equal(t, "13.00", tmpl.Splits[0].Amount.String(), "Problem with amount of template split")
+			assert.Equal(t, "Music/Movies", tmpl.Splits[1].Account.Name, "Problem with account of template split")
+			assert.Equal(t, "-13.00", tmpl.Splits[1].Amount.String(), "Problem with amount of template split")
+		}
+	}
+
+	upcoming := book.Upcoming("2019-06-01", "2019-08-31")
+	if assert.Equal(t, 2, len(upcoming), "Problem with the number of upcoming occurrences") {
+		assert.Equal(t, "2019-07-10", upcoming[0].Date, "Problem with date of first occurrence")
+		assert.Equal(t, sx.ID, upcoming[0].Scheduled, "Problem with scheduled transaction of occurrence")
+		assert.Equal(t, "2019-08-10", upcoming[1].Date, "Problem with date of second occurrence")
+	}
+
+	sx.Enabled = false
+	assert.Empty(t, book.Upcoming("2019-06-01", "2019-08-31"), "Disabled scheduled transactions should not have occurrences")
+}
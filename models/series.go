@@ -0,0 +1,182 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Intervals of a balance series
+const (
+	Day     = "day"
+	Week    = "week" // weeks end on sunday
+	Month   = "month"
+	Quarter = "quarter"
+	Year    = "year"
+)
+
+// maxPeriods limits the length of a series
+const maxPeriods = 100000
+
+// PeriodEnds returns the end dates of the periods of interval between from and to.
+// The last period is truncated to end on to.
+func PeriodEnds(from string, to string, interval string) ([]string, error) {
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, errors.New("end of series is before its start")
+	}
+
+	ends := make([]string, 0)
+	for d := periodEnd(start, interval); ; d = periodEnd(d.AddDate(0, 0, 1), interval) {
+		if d.IsZero() {
+			return nil, fmt.Errorf("unknown interval '%s'", interval)
+		}
+		if !d.Before(end) {
+			ends = append(ends, end.Format("2006-01-02"))
+			break
+		}
+		ends = append(ends, d.Format("2006-01-02"))
+		if len(ends) > maxPeriods {
+			return nil, errors.New("too many periods in series")
+		}
+	}
+	return ends, nil
+}
+
+// periodEnd returns the last day of the period of interval containing d
+func periodEnd(d time.Time, interval string) time.Time {
+	switch interval {
+	case Day:
+		return d
+	case Week:
+		return d.AddDate(0, 0, (7-int(d.Weekday()))%7)
+	case Month:
+		return time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	case Quarter:
+		q := (int(d.Month())-1)/3 + 1
+		return time.Date(d.Year(), time.Month(3*q+1), 0, 0, 0, 0, 0, time.UTC)
+	case Year:
+		return time.Date(d.Year(), 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Time{}
+}
+
+// BalanceSeries returns the balance of the account at the end of each period of interval
+// between opts.From and opts.To, or with flow the sum of the splits during each period.
+// Without opts.From, the series starts at the first split.
+// Conversion to opts.Currency is done with the prices at the end of each period.
+func (a *Account) BalanceSeries(opts BalanceOptions, interval string, flow bool) ([]Balance, error) {
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+	if opts.Currency != nil {
+		opts.Quantity = true // like Balance, the quantities held in each account are converted
+	}
+
+	type entry struct {
+		date      string
+		account   int // index in acts
+		commodity *Commodity
+		amount    Amount
+	}
+	acts := []*Account{a}
+	if opts.Recursive {
+		acts = append(acts, a.Descendants()...)
+	}
+	scu := a.SCU
+	entries := make([]entry, 0)
+	for i, act := range acts {
+		if act.SCU > scu {
+			scu = act.SCU
+		}
+		for _, s := range act.Splits {
+			t := s.Transaction
+			if opts.Type != "" && t.Num != opts.Type {
+				continue
+			}
+			if t.DatePosted > opts.To {
+				continue
+			}
+			entries = append(entries, entry{date: t.DatePosted, account: i, commodity: act.commodityOf(s, opts), amount: s.Amount(opts)})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].date < entries[j].date })
+
+	if opts.From == "" {
+		opts.From = opts.To
+		if len(entries) > 0 {
+			opts.From = entries[0].date
+		}
+	}
+	ends, err := PeriodEnds(opts.From, opts.To, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	// running sums by account and commodity, converted like Balance does account by account
+	amounts := make([]map[*Commodity]Amount, len(acts))
+	reset := func() {
+		for i := range amounts {
+			amounts[i] = make(map[*Commodity]Amount)
+		}
+	}
+	reset()
+
+	series := make([]Balance, 0, len(ends))
+	e := 0
+	for _, end := range ends {
+		for ; e < len(entries) && entries[e].date <= end; e++ {
+			if flow && entries[e].date < opts.From {
+				continue
+			}
+			m := amounts[entries[e].account]
+			m[entries[e].commodity] = m[entries[e].commodity].Add(entries[e].amount)
+		}
+
+		b := Amount{SCU: scu}
+		balance := Balance{Date: end}
+		for _, m := range amounts {
+			if len(m) == 0 {
+				continue
+			}
+			total, unpriced, commodities := opts.total(m, end)
+			b = b.Add(total)
+			for _, c := range unpriced {
+				balance.Unpriced = appendOnce(balance.Unpriced, c)
+			}
+			for _, c := range commodities {
+				balance.commodities = appendOnce(balance.commodities, c)
+			}
+		}
+		sort.Strings(balance.Unpriced)
+		if len(balance.commodities) > 1 {
+			sort.Strings(balance.commodities)
+			balance.Mixed = balance.commodities
+		}
+		if opts.Currency != nil {
+			balance.Currency = opts.Currency.String()
+			if b.SCU < opts.Currency.Fraction {
+				b = b.WithSCU(opts.Currency.Fraction)
+			}
+		}
+		balance.Value = b.Float64()
+		balance.Amount = b
+		series = append(series, balance)
+
+		if flow {
+			reset()
+		}
+	}
+	return series, nil
+}
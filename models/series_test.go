@@ -0,0 +1,104 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var periodEndsTests = []struct {
+	from     string
+	to       string
+	interval string
+	expected []string
+}{
+	{"2019-01-30", "2019-02-01", Day, []string{"2019-01-30", "2019-01-31", "2019-02-01"}},
+	{"2019-07-03", "2019-07-20", Week, []string{"2019-07-07", "2019-07-14", "2019-07-20"}},
+	{"2019-07-07", "2019-07-08", Week, []string{"2019-07-07", "2019-07-08"}},
+	{"2019-01-15", "2019-03-31", Month, []string{"2019-01-31", "2019-02-28", "2019-03-31"}},
+	{"2019-02-10", "2019-11-15", Quarter, []string{"2019-03-31", "2019-06-30", "2019-09-30", "2019-11-15"}},
+	{"2018-06-01", "2019-06-01", Year, []string{"2018-12-31", "2019-06-01"}},
+}
+
+func TestPeriodEnds(t *testing.T) {
+	for _, tt := range periodEndsTests {
+		ends, err := PeriodEnds(tt.from, tt.to, tt.interval)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tt.expected, ends, "Problem with the periods by %s from %s to %s", tt.interval, tt.from, tt.to)
+		}
+	}
+
+	_, err := PeriodEnds("2019-01-01", "2019-12-31", "decade")
+	assert.Error(t, err, "Problem with an unknown interval")
+	_, err = PeriodEnds("2019-12-31", "2019-01-01", Month)
+	assert.Error(t, err, "Problem with a series ending before its start")
+	_, err = PeriodEnds("2019-01-01", "2019/12/31", Month)
+	assert.Error(t, err, "Problem with an invalid date")
+	_, err = PeriodEnds("0001-01-01", "9999-12-31", Day)
+	assert.Error(t, err, "Problem with a series too long")
+}
+
+func TestBalanceSeries(t *testing.T) {
+	root, _ := testTransactions()
+	bank := root.FindByID("1")
+
+	series, err := bank.BalanceSeries(BalanceOptions{To: "2019-03-31"}, Month, false)
+	if assert.NoError(t, err) && assert.Equal(t, 3, len(series), "Problem with the number of periods") {
+		assert.Equal(t, "2019-01-31", series[0].Date, "Problem with the end of a period")
+		assert.Equal(t, "-105.50", series[0].Amount.String(), "Problem with the balance at the end of a period")
+		assert.Equal(t, "-235.50", series[1].Amount.String(), "Problem with the balance at the end of a period")
+		assert.Equal(t, "-235.50", series[2].Amount.String(), "Problem with the balance of a period without splits")
+	}
+
+	series, err = bank.BalanceSeries(BalanceOptions{From: "2019-01-06", To: "2019-02-28"}, Month, true)
+	if assert.NoError(t, err) && assert.Equal(t, 2, len(series), "Problem with the number of periods") {
+		assert.Equal(t, "-45.50", series[0].Amount.String(), "Problem with the flow of a period starting after its first split")
+		assert.Equal(t, "-130.00", series[1].Amount.String(), "Problem with the flow of a period")
+	}
+
+	series, err = root.BalanceSeries(BalanceOptions{From: "2019-01-01", To: "2019-02-28", Recursive: true}, Month, false)
+	if assert.NoError(t, err) && assert.Equal(t, 2, len(series), "Problem with the number of periods") {
+		assert.True(t, series[1].Amount.IsZero(), "Problem with the balance of a recursive series")
+	}
+
+	series, err = root.BalanceSeries(BalanceOptions{}, Month, false)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, len(series), "Problem with the series of an account without splits")
+	}
+}
+
+// TestBalanceSeriesMatchesBalance checks that each point of a series is the balance at its date
+func TestBalanceSeriesMatchesBalance(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usd := book.Commodities.Find(CurrencySpace, "USD")
+
+	for _, opts := range []BalanceOptions{
+		{From: "2019-01-01", To: "2019-12-31", Recursive: true},
+		{From: "2019-01-01", To: "2019-12-31", Recursive: true, Currency: usd, Prices: book.Prices},
+		{From: "2019-01-01", To: "2019-12-31", Recursive: true, Quantity: true},
+	} {
+		book.Root.WalkBFS(func(act *Account) bool {
+			series, err := act.BalanceSeries(opts, Week, false)
+			if assert.NoError(t, err) {
+				for _, b := range series {
+					expected := act.Balance(BalanceOptions{To: b.Date, Recursive: opts.Recursive, Quantity: opts.Quantity, Currency: opts.Currency, Prices: opts.Prices})
+					assert.Equal(t, expected.Amount.String(), b.Amount.String(), "Problem with series of %s at %s", act.Name, b.Date)
+					assert.Equal(t, expected.Unpriced, b.Unpriced, "Problem with unpriced commodities of %s at %s", act.Name, b.Date)
+					assert.Equal(t, expected.Mixed, b.Mixed, "Problem with mixed commodities of %s at %s", act.Name, b.Date)
+				}
+			}
+			return false
+		})
+	}
+
+	series, err := book.Root.BalanceSeries(BalanceOptions{From: "2019-01-01", To: "2019-12-31", Recursive: true}, Year, false)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(series), "Problem with the number of periods") {
+		assert.Equal(t, []string{"CURRENCY:EUR", "CURRENCY:USD"}, series[0].Mixed, "Problem with mixed commodities of series")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+)
+
+// Types of slot values
+const (
+	SlotString   = "string"
+	SlotInteger  = "integer"  // int64
+	SlotDouble   = "double"   // float64
+	SlotNumeric  = "numeric"  // Amount
+	SlotGUID     = "guid"     // string
+	SlotTimespec = "timespec" // string, "2019-06-30 10:59:00 +0000"
+	SlotGDate    = "gdate"    // string, "2019-06-30"
+	SlotFrame    = "frame"    // Slots
+	SlotList     = "list"     // []Slot
+)
+
+// Slot is a typed value of the key-value store (KVP) GnuCash attaches to books, accounts, transactions and splits
+type Slot struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Slots is a frame of slots indexed by key
+type Slots map[string]Slot
+
+// Get returns the slot at path, where the keys of nested frames are separated by "/" like in GnuCash ("options/Accounts")
+func (s Slots) Get(path string) (Slot, bool) {
+	keys := strings.Split(path, "/")
+	frame := s
+	for i, key := range keys {
+		slot, ok := frame[key]
+		if !ok {
+			return Slot{}, false
+		}
+		if i == len(keys)-1 {
+			return slot, true
+		}
+		if frame, ok = slot.Value.(Slots); !ok {
+			return Slot{}, false
+		}
+	}
+	return Slot{}, false
+}
+
+// String returns the value of the slot at path when it is a string, a guid or a date
+func (s Slots) String(path string) string {
+	slot, ok := s.Get(path)
+	if !ok {
+		return ""
+	}
+	v, _ := slot.Value.(string)
+	return v
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const slotsXML = `<slots xmlns:slot="http://www.gnucash.org/XML/slot" xmlns:ts="http://www.gnucash.org/XML/ts">
+  <slot>
+    <slot:key>string</slot:key>
+    <slot:value type="string">Hello</slot:value>
+  </slot>
+  <slot>
+    <slot:key>integer</slot:key>
+    <slot:value type="integer">42</slot:value>
+  </slot>
+  <slot>
+    <slot:key>double</slot:key>
+    <slot:value type="double">1.5</slot:value>
+  </slot>
+  <slot>
+    <slot:key>numeric</slot:key>
+    <slot:value type="numeric">-12345/100</slot:value>
+  </slot>
+  <slot>
+    <slot:key>guid</slot:key>
+    <slot:value type="guid">ee21bcb74ba74708a903632d30f7e087</slot:value>
+  </slot>
+  <slot>
+    <slot:key>timespec</slot:key>
+    <slot:value type="timespec">
+      <ts:date>2019-06-30 10:59:00 +0000</ts:date>
+    </slot:value>
+  </slot>
+  <slot>
+    <slot:key>gdate</slot:key>
+    <slot:value type="gdate">
+      <gdate>2019-06-30</gdate>
+    </slot:value>
+  </slot>
+  <slot>
+    <slot:key>frame</slot:key>
+    <slot:value type="frame">
+      <slot>
+        <slot:key>nested</slot:key>
+        <slot:value type="frame">
+          <slot>
+            <slot:key>leaf</slot:key>
+            <slot:value type="string">deep</slot:value>
+          </slot>
+        </slot:value>
+      </slot>
+      <slot>
+        <slot:key>empty</slot:key>
+        <slot:value type="frame"/>
+      </slot>
+    </slot:value>
+  </slot>
+  <slot>
+    <slot:key>list</slot:key>
+    <slot:value type="list">
+      <slot:value type="string">a</slot:value>
+      <slot:value type="integer">2</slot:value>
+    </slot:value>
+  </slot>
+  <slot>
+    <slot:key>bad integer</slot:key>
+    <slot:value type="integer">forty-two</slot:value>
+  </slot>
+</slots>`
+
+func TestSlots(t *testing.T) {
+	var xs struct {
+		Slots []xmlSlot `xml:"slot"`
+	}
+	if err := xml.Unmarshal([]byte(slotsXML), &xs); err != nil {
+		t.Fatal(err)
+	}
+	slots := newSlots(xs.Slots)
+
+	assert.Equal(t, Slot{Type: SlotString, Value: "Hello"}, slots["string"], "Problem with string slot")
+	assert.Equal(t, Slot{Type: SlotInteger, Value: int64(42)}, slots["integer"], "Problem with integer slot")
+	assert.Equal(t, Slot{Type: SlotDouble, Value: 1.5}, slots["double"], "Problem with double slot")
+	assert.Equal(t, Slot{Type: SlotNumeric, Value: NewAmount(-12345, 100)}, slots["numeric"], "Problem with numeric slot")
+	assert.Equal(t, Slot{Type: SlotGUID, Value: "ee21bcb74ba74708a903632d30f7e087"}, slots["guid"], "Problem with guid slot")
+	assert.Equal(t, Slot{Type: SlotTimespec, Value: "2019-06-30 10:59:00 +0000"}, slots["timespec"], "Problem with timespec slot")
+	assert.Equal(t, Slot{Type: SlotGDate, Value: "2019-06-30"}, slots["gdate"], "Problem with gdate slot")
+	assert.Equal(t, Slot{Type: SlotList, Value: []Slot{{Type: SlotString, Value: "a"}, {Type: SlotInteger, Value: int64(2)}}}, slots["list"], "Problem with list slot")
+	assert.Equal(t, Slot{Type: SlotInteger, Value: "forty-two"}, slots["bad integer"], "Problem with invalid integer slot")
+
+	assert.Equal(t, "deep", slots.String("frame/nested/leaf"), "Problem with slot in nested frames")
+	empty, ok := slots.Get("frame/empty")
+	if assert.True(t, ok, "Problem with empty frame") {
+		assert.Equal(t, Slots{}, empty.Value, "Problem with empty frame")
+	}
+	_, ok = slots.Get("frame/missing")
+	assert.False(t, ok, "Problem with missing slot")
+	_, ok = slots.Get("string/leaf")
+	assert.False(t, ok, "Problem with path through a slot which is not a frame")
+	assert.Equal(t, "", slots.String("integer"), "Problem with string of a slot which is not a string")
+
+	b, err := json.Marshal(slots["frame"])
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"type":"frame","value":{"empty":{"type":"frame","value":{}},"nested":{"type":"frame","value":{"leaf":{"type":"string","value":"deep"}}}}}`, string(b), "Problem with JSON of frame")
+	}
+	b, err = json.Marshal(slots["numeric"])
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"type":"numeric","value":"-123.45"}`, string(b), "Problem with JSON of numeric")
+	}
+
+	assert.Nil(t, newSlots(nil), "Problem with no slots")
+}
+
+func TestLoadSlots(t *testing.T) {
+	book, err := LoadFromFile("testdata/empty.gnucash")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "true", book.Slots.String("remove-color-not-set-slots"), "Problem with book slot")
+	budgeting, ok := book.Slots.Get("options/Budgeting")
+	if assert.True(t, ok, "Problem with book slot in a frame") {
+		assert.Equal(t, SlotFrame, budgeting.Type, "Problem with type of book slot")
+	}
+	assert.Equal(t, "true", book.AccountByPath("Assets").Slots.String("placeholder"), "Problem with account slot")
+	trn := book.Transactions[0]
+	assert.Equal(t, trn.DatePosted, trn.Slots.String("date-posted"), "Problem with transaction gdate slot")
+
+	book, err = LoadFromFile("testdata/multicurrency.gnucash")
+	if !assert.NoError(t, err) {
+		return
+	}
+	splits := book.AccountByPath("Assets:Checking Account").Splits
+	assert.Nil(t, splits[0].Slots, "Problem with split without slots")
+	assert.Equal(t, "FR7630001007941234567890185-20190105", splits[1].Slots.String("online_id"), "Problem with split slot")
+}
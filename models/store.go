@@ -0,0 +1,82 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// Status values
+const (
+	StatusOK    = "ok"
+	StatusError = "error" // last reload has failed, previous book is still served
+)
+
+// Status describes the book currently held by a Store
+type Status struct {
+	File     FileInfo   `json:"file"`
+	LoadedAt time.Time  `json:"loaded_at"`
+	Status   string     `json:"status"`
+	Error    string     `json:"error,omitempty"`
+	ErrorAt  *time.Time `json:"error_at,omitempty"`
+}
+
+// Store owns the book served to concurrent readers.
+// A book must not be modified once given to the Store: readers get a snapshot
+// which stays valid and consistent even if the book is replaced meanwhile.
+type Store struct {
+	mu     sync.RWMutex
+	book   *Book
+	status Status
+}
+
+// NewStore returns a Store holding book
+func NewStore(book *Book) *Store {
+	s := &Store{}
+	s.Replace(book)
+	return s
+}
+
+// Snapshot returns the current book
+func (s *Store) Snapshot() *Book {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.book
+}
+
+// Status returns the status of the current book
+func (s *Store) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Replace atomically replaces the current book
+func (s *Store) Replace(book *Book) {
+	book.index() // built before being shared
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.book = book
+	s.status = Status{File: book.File, LoadedAt: book.LoadedAt, Status: StatusOK}
+}
+
+// SetError records an error while reloading the book, the current book is kept
+func (s *Store) SetError(err error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.Status = StatusError
+	s.status.Error = err.Error()
+	s.status.ErrorAt = &now
+}
+
+// ClearError records that the file of the current book is readable again
+func (s *Store) ClearError() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.Status = StatusOK
+	s.status.Error = ""
+	s.status.ErrorAt = nil
+}
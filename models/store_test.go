@@ -0,0 +1,53 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	root1, trns1 := testTransactions()
+	book1 := &Book{Root: root1, Transactions: trns1}
+	store := NewStore(book1)
+
+	assert.Equal(t, book1, store.Snapshot(), "Problem with current book of the store")
+	assert.Equal(t, StatusOK, store.Status().Status, "Problem with status of the store")
+	assert.Equal(t, "Fuel", store.Snapshot().Account("2").Name, "Problem while retrieve account by ID")
+	assert.Nil(t, store.Snapshot().Account("666"), "Problem while retrieve not existing account by ID")
+
+	store.SetError(errors.New("XML syntax error"))
+	status := store.Status()
+	assert.Equal(t, StatusError, status.Status, "Problem with status of the store after an error")
+	assert.Equal(t, "XML syntax error", status.Error, "Problem with error of the store")
+	assert.NotNil(t, status.ErrorAt, "Problem with time of the error of the store")
+	assert.Equal(t, book1, store.Snapshot(), "Book should be kept after an error")
+
+	// readers keep a consistent snapshot while the book is replaced
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				book := store.Snapshot()
+				act := book.Account("1")
+				assert.Equal(t, book.Root.Children[0], act, "Snapshot is not consistent")
+				act.Balance(BalanceOptions{Recursive: true})
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		root, trns := testTransactions()
+		store.Replace(&Book{Root: root, Transactions: trns})
+	}
+	wg.Wait()
+
+	assert.Equal(t, StatusOK, store.Status().Status, "Problem with status of the store after a replace")
+	assert.True(t, book1 != store.Snapshot(), "Problem with replaced book of the store")
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Transaction is an exchange of value between accounts, each account being affected by one Split
+type Transaction struct {
+	ID          string     `json:"id"`
+	Currency    *Commodity `json:"currency,omitempty"`
+	Num         string     `json:"num,omitempty"`
+	DatePosted  string     `json:"date_posted"` // YYYY-MM-DD
+	DateEntered string     `json:"date_entered,omitempty"`
+	Description string     `json:"description"`
+	Notes       string     `json:"notes,omitempty"`
+	Slots       Slots      `json:"slots,omitempty"`
+	Splits      []*Split   `json:"splits"`
+}
+
+// Split is the part of a transaction affecting one account.
+// Value is expressed in the currency of the transaction, Quantity in the commodity of the account.
+type Split struct {
+	ID              string       `json:"id"`
+	Transaction     *Transaction `json:"-"`
+	Account         *Account     `json:"-"`
+	Lot             *Lot         `json:"-"`
+	Memo            string       `json:"memo,omitempty"`
+	Action          string       `json:"action,omitempty"`
+	ReconciledState string       `json:"reconciled_state"`
+	ReconcileDate   string       `json:"reconcile_date,omitempty"`
+	Value           Amount       `json:"value"`
+	Quantity        Amount       `json:"quantity"`
+	Slots           Slots        `json:"slots,omitempty"`
+}
+
+// Amount returns the quantity or the value of the split depending on opts.Quantity
+func (s *Split) Amount(opts BalanceOptions) Amount {
+	if opts.Quantity {
+		return s.Quantity
+	}
+	return s.Value
+}
+
+// Imbalance returns the sum of the values of the splits, which is zero for a balanced transaction
+func (t *Transaction) Imbalance() Amount {
+	var sum Amount
+	for _, s := range t.Splits {
+		sum = sum.Add(s.Value)
+	}
+	return sum
+}
+
+// MarshalJSON writes the split with the ID of its account and of its lot
+func (s *Split) MarshalJSON() ([]byte, error) {
+	type split Split
+	var account, lot string
+	if s.Account != nil {
+		account = s.Account.ID
+	}
+	if s.Lot != nil {
+		lot = s.Lot.ID
+	}
+	return json.Marshal(struct {
+		*split
+		Account string `json:"account"`
+		Lot     string `json:"lot,omitempty"`
+	}{split: (*split)(s), Account: account, Lot: lot})
+}
+
+// Transactions is a list of transactions
+type Transactions []*Transaction
+
+// FindByID returns the transaction matching ID
+func (ts Transactions) FindByID(ID string) *Transaction {
+	for _, t := range ts {
+		if t.ID == ID {
+			return t
+		}
+	}
+	return nil
+}
+
+// TransactionOptions is the type used as input parameters for the Filter function.
+// Min and Max are compared to the absolute value of the splits, restricted to
+// the splits of Account when it is set.
+type TransactionOptions struct {
+	Account     string // ID
+	From        string
+	To          string
+	Min         *Amount
+	Max         *Amount
+	Description string // case insensitive
+}
+
+// Filter returns the list of transactions matching opts
+func (ts Transactions) Filter(opts TransactionOptions) Transactions {
+	found := make(Transactions, 0)
+	description := strings.ToLower(opts.Description)
+	for _, t := range ts {
+		if opts.From != "" && t.DatePosted < opts.From {
+			continue
+		}
+		if opts.To != "" && t.DatePosted > opts.To {
+			continue
+		}
+		if description != "" && !strings.Contains(strings.ToLower(t.Description), description) {
+			continue
+		}
+		for _, s := range t.Splits {
+			if opts.Account != "" && (s.Account == nil || s.Account.ID != opts.Account) {
+				continue
+			}
+			v := s.Value
+			if v.Sign() < 0 {
+				v = v.Neg()
+			}
+			if opts.Min != nil && v.Cmp(*opts.Min) < 0 {
+				continue
+			}
+			if opts.Max != nil && v.Cmp(*opts.Max) > 0 {
+				continue
+			}
+			found = append(found, t)
+			break
+		}
+	}
+	return found
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTransactions() (*Account, Transactions) {
+	bank := &Account{ID: "1", Name: "Bank", Type: "BANK"}
+	fuel := &Account{ID: "2", Name: "Fuel", Type: "EXPENSE"}
+	food := &Account{ID: "3", Name: "Food", Type: "EXPENSE"}
+	root := &Account{ID: "0", Name: "Root Account", Type: "ROOT", Children: []*Account{bank, fuel, food}}
+
+	trns := Transactions{
+		{ID: "t1", DatePosted: "2019-01-05", Description: "Gas station"},
+		{ID: "t2", DatePosted: "2019-01-10", Description: "Supermarket"},
+		{ID: "t3", DatePosted: "2019-02-03", Description: "Supermarket and gas"},
+	}
+	link := func(t *Transaction, act *Account, value int64) {
+		s := &Split{ID: t.ID + act.ID, Transaction: t, Account: act, Value: NewAmount(value, 100), Quantity: NewAmount(value, 100)}
+		t.Splits = append(t.Splits, s)
+		act.Splits = append(act.Splits, s)
+	}
+	link(trns[0], bank, -6000)
+	link(trns[0], fuel, 6000)
+	link(trns[1], bank, -4550)
+	link(trns[1], food, 4550)
+	link(trns[2], bank, -13000)
+	link(trns[2], food, 8000)
+	link(trns[2], fuel, 5000)
+	return root, trns
+}
+
+func amountPtr(a Amount) *Amount {
+	return &a
+}
+
+var transactionFilterTests = []struct {
+	options  TransactionOptions
+	expected []string
+}{
+	{TransactionOptions{}, []string{"t1", "t2", "t3"}},
+	{TransactionOptions{Account: "2"}, []string{"t1", "t3"}},
+	{TransactionOptions{From: "2019-01-06", To: "2019-01-31"}, []string{"t2"}},
+	{TransactionOptions{Description: "supermarket"}, []string{"t2", "t3"}},
+	{TransactionOptions{Min: amountPtr(NewAmount(100, 1))}, []string{"t3"}},
+	{TransactionOptions{Max: amountPtr(NewAmount(50, 1))}, []string{"t2", "t3"}},
+	{TransactionOptions{Account: "2", Max: amountPtr(NewAmount(50, 1))}, []string{"t3"}},
+	{TransactionOptions{Account: "666"}, []string{}},
+}
+
+func TestTransactionsFilter(t *testing.T) {
+	_, trns := testTransactions()
+
+	for _, tt := range transactionFilterTests {
+		found := trns.Filter(tt.options)
+		ids := make([]string, 0)
+		for _, t := range found {
+			ids = append(ids, t.ID)
+		}
+		assert.Equal(t, tt.expected, ids, "Problem while filtering transactions with %+v", tt.options)
+	}
+
+	assert.Equal(t, trns[1], trns.FindByID("t2"), "Problem while retrieve transaction by ID")
+	assert.Nil(t, trns.FindByID("t666"), "Problem while retrieve not existing transaction by ID")
+}
+
+func TestSplitJSON(t *testing.T) {
+	_, trns := testTransactions()
+
+	b, err := json.Marshal(trns[0].Splits[1])
+	if assert.NoError(t, err) {
+		var split map[string]interface{}
+		json.Unmarshal(b, &split)
+		assert.Equal(t, "2", split["account"], "Problem with account of split in JSON")
+		assert.Equal(t, "60.00", split["value"], "Problem with value of split in JSON")
+	}
+}
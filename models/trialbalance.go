@@ -0,0 +1,96 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// TrialBalanceLine is the balance of an account as a debit or a credit
+type TrialBalanceLine struct {
+	Account AccountRef `json:"account"`
+	Type    string     `json:"type"`
+	Debit   Amount     `json:"debit"`
+	Credit  Amount     `json:"credit"`
+}
+
+// UnbalancedTransaction is a transaction whose splits do not sum to zero
+type UnbalancedTransaction struct {
+	ID          string `json:"id"`
+	Date        string `json:"date"`
+	Description string `json:"description"`
+	Imbalance   Amount `json:"imbalance"`
+}
+
+// TrialBalance lists the balances of the accounts at a date as debits and credits.
+// Total debits and total credits are equal unless some transactions are unbalanced.
+type TrialBalance struct {
+	Date        string                  `json:"date"`
+	Currency    string                  `json:"currency,omitempty"`
+	Accounts    []TrialBalanceLine      `json:"accounts"`
+	TotalDebit  Amount                  `json:"total_debit"`
+	TotalCredit Amount                  `json:"total_credit"`
+	Difference  Amount                  `json:"difference"` // TotalDebit - TotalCredit
+	Balanced    bool                    `json:"balanced"`
+	Unbalanced  []UnbalancedTransaction `json:"unbalanced_transactions"`
+	Unpriced    []string                `json:"unpriced,omitempty"`
+}
+
+// TrialBalance returns the trial balance of the book at opts.To.
+// It lists the leaf accounts and the other accounts having their own splits,
+// and the unbalanced transactions found by Load posted at or before opts.To.
+func (b *Book) TrialBalance(opts BalanceOptions) TrialBalance {
+	if opts.To == "" {
+		opts.To = time.Now().Format("2006-01-02")
+	}
+	opts = BalanceOptions{To: opts.To, Currency: opts.Currency, Prices: opts.Prices}
+
+	tb := TrialBalance{
+		Date:       opts.To,
+		Accounts:   make([]TrialBalanceLine, 0),
+		Unbalanced: make([]UnbalancedTransaction, 0),
+	}
+	if opts.Currency != nil {
+		tb.Currency = opts.Currency.String()
+	}
+
+	if b.Root != nil {
+		acts := b.Root.WalkBFS(func(act *Account) bool {
+			return act != b.Root && (len(act.Children) == 0 || len(act.Splits) > 0)
+		})
+		for _, act := range acts {
+			balance := act.Balance(opts)
+			for _, c := range balance.Unpriced {
+				tb.Unpriced = appendOnce(tb.Unpriced, c)
+			}
+			zero := Amount{SCU: balance.Amount.SCU}
+			line := TrialBalanceLine{Account: act.Ref(), Type: act.Type, Debit: zero, Credit: zero}
+			if balance.Amount.Sign() > 0 {
+				line.Debit = balance.Amount
+			} else if balance.Amount.Sign() < 0 {
+				line.Credit = balance.Amount.Neg()
+			}
+			tb.TotalDebit = tb.TotalDebit.Add(line.Debit)
+			tb.TotalCredit = tb.TotalCredit.Add(line.Credit)
+			tb.Accounts = append(tb.Accounts, line)
+		}
+	}
+	tb.Difference = tb.TotalDebit.Sub(tb.TotalCredit)
+	tb.Balanced = tb.Difference.IsZero()
+	sort.Strings(tb.Unpriced)
+
+	for _, t := range b.Unbalanced {
+		if t.DatePosted > opts.To {
+			continue
+		}
+		tb.Unbalanced = append(tb.Unbalanced, UnbalancedTransaction{
+			ID:          t.ID,
+			Date:        t.DatePosted,
+			Description: t.Description,
+			Imbalance:   t.Imbalance(),
+		})
+	}
+	return tb
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrialBalance(t *testing.T) {
+	book, err := LoadFromFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, len(book.Unbalanced), "Problem with unbalanced transactions of a valid file")
+
+	tb := book.TrialBalance(BalanceOptions{To: "2019-12-31"})
+	assert.Equal(t, 10, len(tb.Accounts), "Problem with the number of accounts of trial balance")
+	assert.Equal(t, "5040.00", tb.TotalDebit.String(), "Problem with total debits")
+	assert.Equal(t, "5040.00", tb.TotalCredit.String(), "Problem with total credits")
+	assert.True(t, tb.Balanced, "Trial balance should be balanced, difference is %s", tb.Difference)
+	assert.Equal(t, 0, len(tb.Unbalanced), "Problem with unbalanced transactions")
+	for _, l := range tb.Accounts {
+		switch l.Account.Name {
+		case "Checking Account":
+			assert.Equal(t, "3584.80", l.Debit.String(), "Problem with debit of %s", l.Account.Name)
+			assert.Equal(t, "0.00", l.Credit.String(), "Problem with credit of %s", l.Account.Name)
+		case "Salary":
+			assert.Equal(t, "0.00", l.Debit.String(), "Problem with debit of %s", l.Account.Name)
+			assert.Equal(t, "4000.00", l.Credit.String(), "Problem with credit of %s", l.Account.Name)
+		case "Auto", "Home", "Assets":
+			t.Errorf("Account %s without splits is not a leaf", l.Account.Name)
+		}
+	}
+}
+
+func TestTrialBalanceUnbalanced(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/multicurrency.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted := strings.Replace(string(data), "<split:value>-4000/100</split:value>", "<split:value>-4100/100</split:value>", 1)
+	book, err := Load(strings.NewReader(corrupted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 1, len(book.Unbalanced), "Problem with unbalanced transactions found by Load") {
+		assert.Equal(t, "Sell AAPL", book.Unbalanced[0].Description, "Problem with unbalanced transaction")
+	}
+
+	tb := book.TrialBalance(BalanceOptions{To: "2019-12-31"})
+	assert.False(t, tb.Balanced, "Trial balance should not be balanced")
+	assert.Equal(t, "-1.00", tb.Difference.String(), "Problem with difference between debits and credits")
+	if assert.Equal(t, 1, len(tb.Unbalanced), "Problem with unbalanced transactions") {
+		assert.Equal(t, "2019-03-01", tb.Unbalanced[0].Date, "Problem with date of unbalanced transaction")
+		assert.Equal(t, "-1.00", tb.Unbalanced[0].Imbalance.String(), "Problem with imbalance of transaction")
+	}
+
+	tb = book.TrialBalance(BalanceOptions{To: "2019-02-28"})
+	assert.True(t, tb.Balanced, "Trial balance should be balanced before the unbalanced transaction")
+	assert.Equal(t, 0, len(tb.Unbalanced), "Problem with unbalanced transactions posted after the date")
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// Watcher reloads a GnuCash file when it is modified on disk.
+// A modification is taken into account only when the file has not changed
+// between two checks, so that a file still being written is not read.
+// On error, the current book is kept and OnError is called. When the file is found
+// again unchanged since the current book has been loaded, OnRecover is called.
+type Watcher struct {
+	Path      string
+	Interval  time.Duration
+	OnLoad    func(book *Book)
+	OnError   func(err error)
+	OnRecover func()
+
+	loaded  FileInfo
+	pending *FileInfo
+	failed  *FileInfo
+	erred   bool // OnError has been called since the last load or recovery
+}
+
+// NewWatcher returns a Watcher for the file from which book has been loaded
+func NewWatcher(book *Book, interval time.Duration, onLoad func(book *Book), onError func(err error), onRecover func()) *Watcher {
+	return &Watcher{
+		Path:      book.File.Path,
+		Interval:  interval,
+		OnLoad:    onLoad,
+		OnError:   onError,
+		OnRecover: onRecover,
+		loaded:    book.File,
+	}
+}
+
+// Check looks for a modification of the file and reloads it if needed.
+// Returns true if a new book has been loaded.
+func (w *Watcher) Check() bool {
+	fi, err := os.Stat(w.Path)
+	if err != nil {
+		w.erred = true
+		w.OnError(err)
+		return false
+	}
+	current := FileInfo{Path: w.Path, ModTime: fi.ModTime(), Size: fi.Size()}
+
+	if !current.Changed(w.loaded) {
+		w.pending = nil
+		w.failed = nil
+		if w.erred {
+			log.Printf("GnuCash file '%s' is back unchanged", w.Path)
+			w.erred = false
+			w.OnRecover()
+		}
+		return false
+	}
+	if w.failed != nil && !current.Changed(*w.failed) {
+		return false
+	}
+	if w.pending == nil || current.Changed(*w.pending) {
+		w.pending = &current
+		return false
+	}
+
+	log.Printf("Reloading modified GnuCash file '%s'", w.Path)
+	w.pending = nil
+	book, err := LoadFromFile(w.Path)
+	if err != nil {
+		log.Printf("Unable to reload GnuCash file '%s', keeping previous data: %s", w.Path, err)
+		w.failed = &current
+		w.erred = true
+		w.OnError(err)
+		return false
+	}
+	w.loaded = book.File
+	w.failed = nil
+	w.erred = false
+	w.OnLoad(book)
+	return true
+}
+
+// Run checks the file every Interval until stop is closed
+func (w *Watcher) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Check()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 VinyMeuh. All rights reserved.
+// Use of the source code is governed by a MIT-style license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnc-api-d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data, err := ioutil.ReadFile("testdata/empty.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "book.gnucash")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	book, err := LoadFromFile(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, path, book.File.Path, "Problem with path of loaded file")
+	assert.Equal(t, int64(len(data)), book.File.Size, "Problem with size of loaded file")
+
+	var loaded *Book
+	var lastErr error
+	w := NewWatcher(book, time.Second, func(b *Book) { loaded = b }, func(err error) { lastErr = err }, func() {})
+
+	assert.False(t, w.Check(), "Not modified file should not be reloaded")
+
+	// a modified file is reloaded once it is stable
+	touch := func(content []byte, mtime time.Time) {
+		if err := ioutil.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, mtime, mtime)
+	}
+	touch(data, book.File.ModTime.Add(time.Minute))
+	assert.False(t, w.Check(), "Modified file should be reloaded only when stable")
+	assert.True(t, w.Check(), "Modified file should be reloaded")
+	if assert.NotNil(t, loaded, "Reloaded book should be notified") {
+		assert.Equal(t, 64, len(loaded.Root.Descendants())+1, "Problem with reloaded book")
+	}
+	assert.False(t, w.Check(), "Reloaded file should not be reloaded twice")
+
+	// a corrupted file is reported and not retried until modified again
+	loaded = nil
+	touch([]byte("<gnc-v2"), book.File.ModTime.Add(2*time.Minute))
+	w.Check()
+	assert.False(t, w.Check(), "Corrupted file should not be loaded")
+	assert.Error(t, lastErr, "Corrupted file should be reported")
+	assert.Nil(t, loaded, "Corrupted file should not replace current book")
+	lastErr = nil
+	w.Check()
+	assert.Nil(t, lastErr, "Corrupted file should not be reloaded until modified")
+
+	// a missing file is reported
+	os.Remove(path)
+	w.Check()
+	assert.Error(t, lastErr, "Missing file should be reported")
+}
+
+func TestWatcherRecover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnc-api-d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data, err := ioutil.ReadFile("testdata/empty.gnucash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "book.gnucash")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	book, err := LoadFromFile(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	store := NewStore(book)
+	w := NewWatcher(book, time.Second, store.Replace, store.SetError, store.ClearError)
+
+	// the file is briefly unavailable, then comes back with the same modification time and size
+	hidden := filepath.Join(dir, "book.gnucash.tmp")
+	if err := os.Rename(path, hidden); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, w.Check(), "Missing file should not be reloaded")
+	assert.Equal(t, StatusError, store.Status().Status, "Missing file should be reported")
+	if err := os.Rename(hidden, path); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, w.Check(), "Unchanged file should not be reloaded")
+	status := store.Status()
+	assert.Equal(t, StatusOK, status.Status, "Error should be cleared when the file is back unchanged")
+	assert.Empty(t, status.Error, "Error should be cleared when the file is back unchanged")
+	assert.Nil(t, status.ErrorAt, "Error should be cleared when the file is back unchanged")
+	assert.True(t, book == store.Snapshot(), "Unchanged file should not replace current book")
+}